@@ -0,0 +1,222 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Async client errors.
+var (
+	ErrQueueFull   = errors.New("pushover: async queue is full")
+	ErrQueueClosed = errors.New("pushover: async queue is closed")
+)
+
+// DefaultAsyncBufferSize is the default size of AsyncClient buffer.
+const DefaultAsyncBufferSize = 100
+
+// OverflowPolicy defines AsyncClient behavior when its buffer is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	BlockOnFull OverflowPolicy = iota // Enqueue blocks until there is space in the buffer
+	DropOnFull                        // Enqueue drops the message and returns ErrQueueFull
+)
+
+// AsyncClient lanes.
+const (
+	highLane   = iota // high and emergency priority
+	normalLane        // normal priority
+	lowLane           // low and lowest priority
+	lanes
+)
+
+// defaultLaneWeights are default AsyncOptions.Weights.
+var defaultLaneWeights = [lanes]int{4, 2, 1}
+
+// AsyncOptions configure AsyncClient.
+type AsyncOptions struct {
+	BufferSize int            // maximal number of buffered messages, defaults to DefaultAsyncBufferSize
+	Overflow   OverflowPolicy // behavior when buffer is full, defaults to BlockOnFull
+
+	// Weights of high (including emergency), normal, and low (including lowest) priority lanes, defaults to {4, 2, 1}.
+	// In every round, up to that many messages are sent from each non-empty lane.
+	Weights [3]int
+
+	// OnDone, if set, is called after each message is sent or failed to be sent.
+	OnDone func(message *Message, err error)
+}
+
+// AsyncClient sends messages in the background, one by one.
+//
+// Messages are buffered in separate lanes for high, normal, and low priorities,
+// and dequeued with weighted round-robin, so sustained high priority traffic does not
+// starve lower priorities. Within a lane, messages are sent in the order of enqueueing.
+type AsyncClient struct {
+	sender  Sender
+	opts    AsyncOptions
+	done    chan struct{}
+	m       sync.Mutex
+	cond    *sync.Cond // signaled on lanes and closed changes
+	lanes   [lanes][]*Message
+	credits [lanes]int
+	size    int
+	closed  bool
+	pending int // buffered and being sent
+	flushed []chan struct{}
+}
+
+// NewAsyncClient creates a new async client sending messages with given sender, and starts it.
+// Client timeout (see WithTimeout) should be set, as messages are sent without deadline.
+func NewAsyncClient(sender Sender, opts AsyncOptions) *AsyncClient {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultAsyncBufferSize
+	}
+	for i, w := range opts.Weights {
+		if w <= 0 {
+			opts.Weights[i] = defaultLaneWeights[i]
+		}
+	}
+
+	c := &AsyncClient{
+		sender:  sender,
+		opts:    opts,
+		done:    make(chan struct{}),
+		credits: opts.Weights,
+	}
+	c.cond = sync.NewCond(&c.m)
+	go c.run()
+	return c
+}
+
+// Enqueue adds message to the buffer for sending.
+// If the buffer is full, it blocks or returns ErrQueueFull depending on the overflow policy.
+// It returns ErrQueueClosed after Close.
+func (c *AsyncClient) Enqueue(message *Message) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for !c.closed && c.size >= c.opts.BufferSize {
+		if c.opts.Overflow == DropOnFull {
+			return ErrQueueFull
+		}
+		c.cond.Wait()
+	}
+	if c.closed {
+		return ErrQueueClosed
+	}
+
+	l := lane(message.Priority)
+	c.lanes[l] = append(c.lanes[l], message)
+	c.size++
+	c.pending++
+	c.cond.Broadcast()
+	return nil
+}
+
+// Flush waits until all enqueued messages are sent or ctx is canceled.
+func (c *AsyncClient) Flush(ctx context.Context) error {
+	c.m.Lock()
+	if c.pending == 0 {
+		c.m.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	c.flushed = append(c.flushed, ch)
+	c.m.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ch:
+		return nil
+	}
+}
+
+// Close stops accepting new messages and waits until all enqueued messages are sent.
+func (c *AsyncClient) Close() {
+	c.m.Lock()
+	c.closed = true
+	c.cond.Broadcast()
+	c.m.Unlock()
+
+	<-c.done
+}
+
+// lane returns lane index for given priority.
+func lane(p Priority) int {
+	switch {
+	case p >= HighPriority:
+		return highLane
+	case p == NormalPriority:
+		return normalLane
+	default:
+		return lowLane
+	}
+}
+
+// run sends enqueued messages until client is closed and buffer is empty.
+func (c *AsyncClient) run() {
+	defer close(c.done)
+
+	for {
+		m := c.next()
+		if m == nil {
+			return
+		}
+
+		err := c.sender.SendMessage(context.Background(), m)
+		if c.opts.OnDone != nil {
+			c.opts.OnDone(m, err)
+		}
+		c.finish()
+	}
+}
+
+// next waits for and removes the next message to send using weighted round-robin over lanes.
+// It returns nil if client is closed and buffer is empty.
+func (c *AsyncClient) next() *Message {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for c.size == 0 {
+		if c.closed {
+			return nil
+		}
+		c.cond.Wait()
+	}
+
+	for {
+		for l := range c.lanes {
+			if len(c.lanes[l]) == 0 || c.credits[l] == 0 {
+				continue
+			}
+
+			m := c.lanes[l][0]
+			c.lanes[l][0] = nil
+			c.lanes[l] = c.lanes[l][1:]
+			c.credits[l]--
+			c.size--
+			c.cond.Broadcast()
+			return m
+		}
+
+		// start a new round
+		c.credits = c.opts.Weights
+	}
+}
+
+// finish decrements pending messages counter and wakes up Flush callers when it reaches zero.
+func (c *AsyncClient) finish() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.pending--
+	if c.pending == 0 {
+		for _, ch := range c.flushed {
+			close(ch)
+		}
+		c.flushed = nil
+	}
+}
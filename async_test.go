@@ -0,0 +1,103 @@
+package pushover
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncClient(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	var m sync.Mutex
+	var done []string
+	ac := NewAsyncClient(c, AsyncOptions{
+		OnDone: func(message *Message, err error) {
+			assert.NoError(t, err)
+			m.Lock()
+			done = append(done, message.Message)
+			m.Unlock()
+		},
+	})
+
+	require.NoError(t, ac.Enqueue(&Message{User: testUser, Message: "1"}))
+	require.NoError(t, ac.Enqueue(&Message{User: testUser, Message: "2"}))
+	require.NoError(t, ac.Flush(context.Background()))
+
+	m.Lock()
+	assert.Equal(t, []string{"1", "2"}, done)
+	m.Unlock()
+
+	ac.Close()
+	assert.Equal(t, ErrQueueClosed, ac.Enqueue(&Message{User: testUser, Message: "3"}))
+}
+
+func TestAsyncClientDropOnFull(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ac := NewAsyncClient(c, AsyncOptions{BufferSize: 1, Overflow: DropOnFull})
+
+	// one message may be taken by the sender, one fits in the buffer
+	var full bool
+	for i := 0; i < 3; i++ {
+		if err := ac.Enqueue(&Message{User: testUser, Message: "message"}); err != nil {
+			assert.Equal(t, ErrQueueFull, err)
+			full = true
+		}
+	}
+	assert.True(t, full)
+
+	close(release)
+	require.NoError(t, ac.Flush(context.Background()))
+	ac.Close()
+}
+
+// senderFunc is a Sender implemented by a function.
+type senderFunc func(ctx context.Context, message *Message) error
+
+func (f senderFunc) SendMessage(ctx context.Context, message *Message, opts ...SendOption) error {
+	return f(ctx, message)
+}
+
+func TestAsyncClientLanes(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var sent []string
+	ac := NewAsyncClient(senderFunc(func(ctx context.Context, message *Message) error {
+		if message.Message == "block" {
+			close(started)
+			<-release
+			return nil
+		}
+		sent = append(sent, message.Message)
+		return nil
+	}), AsyncOptions{})
+
+	require.NoError(t, ac.Enqueue(&Message{Message: "block"}))
+	<-started
+
+	// sustained high priority load with a few low priority messages
+	for i := 0; i < 12; i++ {
+		require.NoError(t, ac.Enqueue(&Message{Message: "h", Priority: HighPriority}))
+		if i%4 == 0 {
+			require.NoError(t, ac.Enqueue(&Message{Message: "l", Priority: LowPriority}))
+		}
+	}
+
+	close(release)
+	ac.Close()
+
+	expected := strings.Split("hhhhlhhhhlhhhhl", "")
+	assert.Equal(t, expected, sent)
+}
@@ -0,0 +1,206 @@
+package pushover
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// defaultAttachmentName is the attachment file name used when Message.AttachmentName is empty.
+const defaultAttachmentName = "attachment"
+
+// attachmentError represents a local attachment failure, such as too large size or read error.
+// It is not retryable.
+type attachmentError struct {
+	err error
+}
+
+// Error implements error interface.
+func (e *attachmentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *attachmentError) Unwrap() error {
+	return e.err
+}
+
+// Retryable returns false: repeating the request will not fix attachment.
+func (e *attachmentError) Retryable() bool {
+	return false
+}
+
+// attachment represents message attachment sent as a part of multipart request body.
+type attachment struct {
+	r           io.Reader
+	name        string
+	contentType string
+	seeker      io.Seeker // nil if r is not seekable
+	start       int64     // starting offset of seekable r
+	used        bool      // true if r was already (partially) read
+
+	wg  sync.WaitGroup // for writing goroutines
+	m   sync.Mutex
+	err *attachmentError // set by the writing goroutine
+}
+
+// newAttachment returns attachment for given message, or nil if message has none.
+// Starting offset of seekable reader is recorded, so it can be read again by retries and by next sends.
+func newAttachment(message *Message) *attachment {
+	if message.Attachment == nil {
+		return nil
+	}
+
+	a := &attachment{
+		r:           message.Attachment,
+		name:        message.AttachmentName,
+		contentType: message.AttachmentType,
+	}
+	if a.name == "" {
+		a.name = defaultAttachmentName
+	}
+	if a.contentType == "" {
+		a.contentType = "application/octet-stream"
+	}
+	if s, ok := a.r.(io.Seeker); ok {
+		if start, err := s.Seek(0, io.SeekCurrent); err == nil {
+			a.seeker, a.start = s, start
+		}
+	}
+	return a
+}
+
+// multipart returns multipart request body with given data fields and attachment, and its content type.
+//
+// Body is written by a separate goroutine through a pipe, so attachment is never buffered in memory as a whole.
+// Caller should close returned body. Attachment is rewound for repeated calls if it implements io.Seeker;
+// otherwise, repeated calls return an error.
+func (a *attachment) multipart(data url.Values) (io.ReadCloser, string, error) {
+	a.wg.Wait()
+
+	if a.used {
+		if a.seeker == nil {
+			return nil, "", &attachmentError{errors.New("pushover: attachment can't be read again")}
+		}
+		if _, err := a.seeker.Seek(a.start, io.SeekStart); err != nil {
+			return nil, "", &attachmentError{err}
+		}
+	}
+	a.used = true
+	a.setErr(nil)
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		pw.CloseWithError(a.write(mw, data))
+	}()
+
+	return pr, mw.FormDataContentType(), nil
+}
+
+// reset rewinds seekable attachment to its starting offset after the last multipart body is written,
+// so the same reader could be sent again (for example, by message copies for other recipients).
+func (a *attachment) reset() {
+	a.wg.Wait()
+
+	if a.used && a.seeker != nil {
+		_, _ = a.seeker.Seek(a.start, io.SeekStart)
+	}
+}
+
+// write writes data fields and attachment to mw and closes it.
+func (a *attachment) write(mw *multipart.Writer, data url.Values) error {
+	for k, vs := range data {
+		for _, v := range vs {
+			if err := mw.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment"; filename="%s"`, quoteEscaper.Replace(a.name)))
+	h.Set("Content-Type", a.contentType)
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(part, &attachmentReader{a: a, r: io.LimitReader(a.r, MaxAttachmentBytes+1)})
+	if err != nil {
+		return err
+	}
+	switch {
+	case n == 0:
+		// most likely, non-seekable reader was already read by a previous send
+		err = &attachmentError{errors.New("pushover: attachment is empty or was already read")}
+	case n > MaxAttachmentBytes:
+		err = &attachmentError{fmt.Errorf("pushover: attachment is too large (max %d bytes)", MaxAttachmentBytes)}
+	}
+	if err != nil {
+		a.setErr(err.(*attachmentError))
+		return err
+	}
+
+	return mw.Close()
+}
+
+// setErr sets local attachment error.
+func (a *attachment) setErr(err *attachmentError) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.err = err
+}
+
+// localErr returns local attachment error of the last multipart body, or nil.
+// It should be used to distinguish them from network errors.
+func (a *attachment) localErr() error {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.err == nil {
+		return nil
+	}
+	return a.err
+}
+
+// attachmentReader records read errors as local attachment errors.
+type attachmentReader struct {
+	a *attachment
+	r io.Reader
+}
+
+// Read implements io.Reader.
+func (r *attachmentReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = &attachmentError{fmt.Errorf("pushover: failed to read attachment: %w", err)}
+		r.a.setErr(err.(*attachmentError))
+	}
+	return n, err
+}
+
+// attachmentSize returns attachment size if it can be determined without reading, or -1.
+func attachmentSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
+// quoteEscaper escapes quoted file name in Content-Disposition header the same way as mime/multipart.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
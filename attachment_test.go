@@ -0,0 +1,173 @@
+package pushover
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachment(t *testing.T) {
+	setFastBackoff(t)
+
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			rw.WriteHeader(400)
+			return
+		}
+		assert.Equal(t, testUser, req.FormValue("user"))
+		assert.Equal(t, "message", req.FormValue("message"))
+
+		f, h, err := req.FormFile("attachment")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer f.Close()
+		b, _ := io.ReadAll(f)
+		assert.Equal(t, "image data", string(b))
+		assert.Equal(t, "image.jpg", h.Filename)
+		assert.Equal(t, "image/jpeg", h.Header.Get("Content-Type"))
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			rw.WriteHeader(500)
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx := context.Background()
+
+	m := &Message{
+		User:           testUser,
+		Message:        "message",
+		Attachment:     strings.NewReader("image data"),
+		AttachmentName: "image.jpg",
+		AttachmentType: "image/jpeg",
+	}
+	// strings.Reader is rewound for retry
+	require.NoError(t, c.SendMessage(ctx, m, WithRetries(1)))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	t.Run("TooLarge", func(t *testing.T) {
+		m := &Message{
+			User:       testUser,
+			Message:    "message",
+			Attachment: bytes.NewReader(make([]byte, MaxAttachmentBytes+1)),
+		}
+		err := c.SendMessage(ctx, m)
+		require.EqualError(t, err, "pushover: attachment is too large: 5242881 bytes (max 5242880)")
+	})
+}
+
+func TestAttachmentErrorsAreFatal(t *testing.T) {
+	setFastBackoff(t)
+
+	var attempts int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		io.Copy(io.Discard, req.Body)
+		rw.WriteHeader(500)
+	}, WithRequestHook(func(req *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+	}))
+
+	ctx := context.Background()
+
+	t.Run("TooLarge", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+		m := &Message{
+			User:       testUser,
+			Message:    "message",
+			Attachment: io.MultiReader(bytes.NewReader(make([]byte, MaxAttachmentBytes+1))),
+		}
+		err := c.SendMessage(ctx, m, WithRetries(3))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "attachment is too large")
+		var fatal *FatalError
+		assert.True(t, errors.As(err, &fatal))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("NotSeeker", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+		m := &Message{
+			User:       testUser,
+			Message:    "message",
+			Attachment: io.MultiReader(strings.NewReader("data")),
+		}
+		err := c.SendMessage(ctx, m, WithRetries(3))
+		require.EqualError(t, err, "pushover: attachment can't be read again")
+		var fatal *FatalError
+		assert.True(t, errors.As(err, &fatal))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+}
+
+func TestAttachmentManyRecipients(t *testing.T) {
+	var m sync.Mutex
+	var attachments []string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			rw.WriteHeader(400)
+			return
+		}
+		f, _, err := req.FormFile("attachment")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer f.Close()
+		b, _ := io.ReadAll(f)
+
+		m.Lock()
+		attachments = append(attachments, string(b))
+		m.Unlock()
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx := context.Background()
+	users := []string{testUser, "gznej3rKEVAvPUxu9vvNnqpmZpokzF"}
+
+	t.Run("Seeker", func(t *testing.T) {
+		attachments = nil
+		r := bytes.NewReader([]byte("skip image data"))
+		_, err := r.Seek(5, io.SeekStart)
+		require.NoError(t, err)
+
+		_, err = c.SendToMany(ctx, users, &Message{Message: "message", Attachment: r})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"image data", "image data"}, attachments)
+	})
+
+	t.Run("NotSeeker", func(t *testing.T) {
+		attachments = nil
+		r := io.MultiReader(strings.NewReader("image data"))
+
+		res, err := c.SendToMany(ctx, users, &Message{Message: "message", Attachment: r})
+		require.Error(t, err)
+		assert.Equal(t, []string{"image data"}, attachments)
+		require.NoError(t, res[0].Err)
+		require.EqualError(t, res[1].Err, "pushover: attachment is empty or was already read")
+		var fatal *FatalError
+		assert.True(t, errors.As(res[1].Err, &fatal))
+	})
+}
+
+func TestAttachmentNotSeeker(t *testing.T) {
+	a := newAttachment(&Message{Attachment: io.MultiReader(strings.NewReader("data"))})
+	rc, _, err := a.multipart(nil)
+	require.NoError(t, err)
+	_, err = io.ReadAll(rc)
+	require.NoError(t, err)
+	rc.Close()
+
+	_, _, err = a.multipart(nil)
+	require.EqualError(t, err, "pushover: attachment can't be read again")
+}
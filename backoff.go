@@ -0,0 +1,64 @@
+package pushover
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff configures exponential backoff with jitter between retries.
+type Backoff struct {
+	Initial    time.Duration // delay after the first attempt
+	Multiplier float64       // delay multiplier for each next attempt, 1 if less than 1
+	Max        time.Duration // maximal delay, not limited if zero
+	MaxElapsed time.Duration // no retries are made after that time since the first attempt, not limited if zero
+	Jitter     float64       // randomization factor from 0 to 1: delay is randomly changed by up to that fraction
+}
+
+// defaultBackoff is used when WithBackoff is not set; variable for tests.
+var defaultBackoff = Backoff{
+	Initial:    5 * time.Second,
+	Multiplier: 2,
+	Max:        time.Minute,
+	Jitter:     0.5,
+}
+
+// Delay returns delay after given attempt (starting from 0), including jitter.
+func (b Backoff) Delay(attempt int) time.Duration {
+	m := b.Multiplier
+	if m < 1 {
+		m = 1
+	}
+
+	d := float64(b.Initial) * math.Pow(m, float64(attempt))
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// RetryPolicy configures Client.SendMessageWithRetries.
+type RetryPolicy struct {
+	MaxRetries int     // maximal number of retries after the first attempt
+	Backoff    Backoff // delays between attempts, the same as for WithRetries if zero
+
+	// Retryable returns true if sending should be retried after given error, defaults to IsRetryable.
+	Retryable func(err error) bool
+}
+
+// SendMessageWithRetries sends given message, retrying failed attempts according to policy.
+// It stops when ctx is canceled, both between attempts and during them.
+func (c *Client) SendMessageWithRetries(ctx context.Context, message *Message, policy RetryPolicy) error {
+	opts := []SendOption{WithRetries(policy.MaxRetries)}
+	if policy.Backoff != (Backoff{}) {
+		opts = append(opts, WithBackoff(policy.Backoff))
+	}
+	if policy.Retryable != nil {
+		opts = append(opts, withRetryable(policy.Retryable))
+	}
+	return c.SendMessage(ctx, message, opts...)
+}
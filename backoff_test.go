@@ -0,0 +1,99 @@
+package pushover
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setFastBackoff replaces the default backoff with 1 millisecond delays for the duration of the test.
+func setFastBackoff(t *testing.T) {
+	t.Helper()
+
+	old := defaultBackoff
+	defaultBackoff = Backoff{Initial: time.Millisecond}
+	t.Cleanup(func() { defaultBackoff = old })
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Multiplier: 2, Max: 5 * time.Second}
+	assert.Equal(t, time.Second, b.Delay(0))
+	assert.Equal(t, 2*time.Second, b.Delay(1))
+	assert.Equal(t, 4*time.Second, b.Delay(2))
+	assert.Equal(t, 5*time.Second, b.Delay(3))
+	assert.Equal(t, 5*time.Second, b.Delay(100))
+
+	assert.Equal(t, time.Second, Backoff{Initial: time.Second}.Delay(10))
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := b.Delay(1)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, 3*time.Second)
+	}
+}
+
+func TestWithBackoff(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.WriteHeader(500)
+	})
+
+	ctx := context.Background()
+
+	b := Backoff{Initial: 10 * time.Millisecond, Multiplier: 2}
+	start := time.Now()
+	require.Error(t, c.Send(ctx, testUser, "message", WithRetries(3), WithBackoff(b)))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+
+	// next delay exceeds max elapsed time
+	atomic.StoreInt32(&requests, 0)
+	b.MaxElapsed = 50 * time.Millisecond
+	require.Error(t, c.Send(ctx, testUser, "message", WithRetries(10), WithBackoff(b)))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestSendMessageWithRetries(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			rw.WriteHeader(500)
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx := context.Background()
+	m := &Message{User: testUser, Message: "message"}
+	policy := RetryPolicy{MaxRetries: 5, Backoff: Backoff{Initial: time.Millisecond}}
+
+	require.NoError(t, c.SendMessageWithRetries(ctx, m, policy))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	t.Run("Retryable", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		policy := policy
+		policy.Retryable = func(err error) bool { return false }
+		require.Error(t, c.SendMessageWithRetries(ctx, m, policy))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		policy := RetryPolicy{MaxRetries: 5, Backoff: Backoff{Initial: time.Hour}}
+		start := time.Now()
+		require.Error(t, c.SendMessageWithRetries(ctx, m, policy))
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+}
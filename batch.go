@@ -0,0 +1,151 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// glanceBatchInterval is a minimal interval between starting glance updates in SendGlances.
+const glanceBatchInterval = 50 * time.Millisecond
+
+// SendGlances sends given glances using at most concurrency parallel requests,
+// starting them not faster than one per 50 milliseconds.
+// It returns a slice of errors with the same length and order as glances; nil means success.
+func (c *Client) SendGlances(ctx context.Context, glances []*Glance, concurrency int) []error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	res := make([]error, len(glances))
+	sem := make(chan struct{}, concurrency)
+	ticker := time.NewTicker(glanceBatchInterval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	for i, g := range glances {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(glances); j++ {
+				res[j] = err
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, g *Glance) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			res[i] = c.SendGlance(ctx, g)
+		}(i, g)
+	}
+
+	wg.Wait()
+	return res
+}
+
+// RecipientResult represents the result of sending a message to a single recipient.
+type RecipientResult struct {
+	User    string // user/group key
+	Receipt string // receipt for emergency priority messages
+	Err     error  // nil on success
+}
+
+// SendToMany sends copies of given message to each of given users one by one.
+// Message.User is ignored.
+// It returns results with the same length and order as users,
+// and all failures joined with errors.Join, or nil if all sends succeeded.
+func (c *Client) SendToMany(ctx context.Context, users []string, message *Message) ([]RecipientResult, error) {
+	res := make([]RecipientResult, len(users))
+	var errs []error
+	for i, u := range users {
+		m := *message
+		m.User = u
+
+		res[i].User = u
+		r, err := c.SendMessageWithResponse(ctx, &m)
+		if err != nil {
+			res[i].Err = err
+			errs = append(errs, fmt.Errorf("pushover: failed to send to %s: %w", u, err))
+			continue
+		}
+		if r != nil {
+			res[i].Receipt = r.Receipt
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// BatchResult represents the result of sending a single message with SendBatch.
+type BatchResult struct {
+	Response *Response // nil on failure
+	Err      error     // nil on success
+}
+
+// SendBatch sends given messages using a pool of concurrency workers.
+// Once Pushover API reports that rate limit is exceeded, remaining messages are not sent
+// and get the same error.
+// It returns a slice of results with the same length and order as messages.
+func (c *Client) SendBatch(ctx context.Context, messages []*Message, concurrency int) []BatchResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	res := make([]BatchResult, len(messages))
+	indexes := make(chan int)
+
+	var m sync.Mutex
+	var rateLimitErr error
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				m.Lock()
+				err := rateLimitErr
+				m.Unlock()
+				if err != nil {
+					res[i].Err = err
+					continue
+				}
+
+				res[i].Response, res[i].Err = c.SendMessageWithResponse(ctx, messages[i])
+				if isRateLimited(res[i].Err) {
+					m.Lock()
+					rateLimitErr = res[i].Err
+					m.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range messages {
+		if err := ctx.Err(); err != nil {
+			res[i].Err = err
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+	return res
+}
@@ -0,0 +1,110 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendGlances(t *testing.T) {
+	var running, maxRunning int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		if req.FormValue("user") == "bad" {
+			rw.WriteHeader(400)
+			rw.Write([]byte(`{"user":"invalid","errors":["user key is invalid"],"status":0,"request":"r"}`))
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	text := "text"
+	glances := []*Glance{
+		{User: "good", Text: &text},
+		{User: "bad", Text: &text},
+		{User: "good", Text: &text},
+		{User: "good", Text: &text},
+	}
+	res := c.SendGlances(context.Background(), glances, 2)
+	require.Len(t, res, 4)
+	assert.NoError(t, res[0])
+	assert.Error(t, res[1])
+	assert.NoError(t, res[2])
+	assert.NoError(t, res[3])
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+}
+
+func TestSendToMany(t *testing.T) {
+	const badUser = "uBadBadBadBadBadBadBadBadBadBa"
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if req.FormValue("user") == badUser {
+			rw.WriteHeader(400)
+			rw.Write([]byte(`{"user":"invalid","errors":["user identifier is invalid"],"status":0,"request":"r"}`))
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r","receipt":"` + req.FormValue("user") + `"}`))
+	})
+
+	res, err := c.SendToMany(context.Background(), []string{testUser, badUser}, &Message{Message: "message", Priority: EmergencyPriority})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidUser))
+	assert.Contains(t, err.Error(), badUser)
+
+	require.Len(t, res, 2)
+	assert.Equal(t, RecipientResult{User: testUser, Receipt: testUser}, res[0])
+	assert.Equal(t, badUser, res[1].User)
+	assert.Error(t, res[1].Err)
+}
+
+func TestSendBatch(t *testing.T) {
+	var requests, running, maxRunning int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		if atomic.AddInt32(&requests, 1) > 5 {
+			rw.WriteHeader(429)
+			rw.Write([]byte(`{"status":0,"errors":["application is over its limit"]}`))
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	messages := make([]*Message, 20)
+	for i := range messages {
+		messages[i] = &Message{User: testUser, Message: "message"}
+	}
+	res := c.SendBatch(context.Background(), messages, 3)
+	require.Len(t, res, 20)
+	var sent int
+	for _, r := range res {
+		if r.Err == nil {
+			sent++
+			assert.Equal(t, "r", r.Response.Request)
+			continue
+		}
+		assert.True(t, isRateLimited(r.Err))
+	}
+	assert.Equal(t, 5, sent)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+	assert.Less(t, atomic.LoadInt32(&requests), int32(20))
+}
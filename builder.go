@@ -0,0 +1,92 @@
+package pushover
+
+import (
+	"time"
+)
+
+// MessageBuilder builds messages with a fluent API.
+type MessageBuilder struct {
+	m Message
+}
+
+// NewMessage returns a new builder for message with given user/group key and text.
+func NewMessage(user, text string) *MessageBuilder {
+	return &MessageBuilder{
+		m: Message{
+			User:    user,
+			Message: text,
+		},
+	}
+}
+
+// Title sets message title.
+func (b *MessageBuilder) Title(title string) *MessageBuilder {
+	b.m.Title = title
+	return b
+}
+
+// URL sets supplementary URL and its title (which may be empty).
+func (b *MessageBuilder) URL(url, title string) *MessageBuilder {
+	b.m.URL = url
+	b.m.URLTitle = title
+	return b
+}
+
+// Priority sets message priority.
+func (b *MessageBuilder) Priority(priority Priority) *MessageBuilder {
+	b.m.Priority = priority
+	return b
+}
+
+// Emergency sets emergency priority with given retry and expire (in seconds) and callback URL.
+// Zero retry and expire values are replaced with defaults when message is sent.
+func (b *MessageBuilder) Emergency(retry, expire int, callback string) *MessageBuilder {
+	b.m.Priority = EmergencyPriority
+	b.m.Retry = retry
+	b.m.Expire = expire
+	b.m.Callback = callback
+	return b
+}
+
+// Sound sets message sound.
+func (b *MessageBuilder) Sound(sound Sound) *MessageBuilder {
+	b.m.Sound = sound
+	return b
+}
+
+// Device adds device names to send message to.
+func (b *MessageBuilder) Device(devices ...string) *MessageBuilder {
+	b.m.Devices = append(b.m.Devices, devices...)
+	return b
+}
+
+// Timestamp sets message time.
+func (b *MessageBuilder) Timestamp(t time.Time) *MessageBuilder {
+	b.m.Timestamp = t
+	return b
+}
+
+// HTML enables HTML formatting.
+func (b *MessageBuilder) HTML() *MessageBuilder {
+	b.m.HTML = true
+	b.m.Monospace = false
+	return b
+}
+
+// Monospace enables monospace formatting.
+func (b *MessageBuilder) Monospace() *MessageBuilder {
+	b.m.Monospace = true
+	b.m.HTML = false
+	return b
+}
+
+// Build validates and returns built message.
+// Builder can be used again after that; returned message is not affected.
+func (b *MessageBuilder) Build() (*Message, error) {
+	m := b.m
+	m.Devices = append([]string(nil), b.m.Devices...)
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
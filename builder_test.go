@@ -0,0 +1,40 @@
+package pushover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBuilder(t *testing.T) {
+	b := NewMessage(testUser, "text").
+		Title("title").
+		Priority(HighPriority).
+		Sound(SirenSound).
+		Device("iphone").
+		URL("https://example.com", "example").
+		HTML()
+
+	m, err := b.Build()
+	require.NoError(t, err)
+	expected := &Message{
+		User:     testUser,
+		Message:  "text",
+		Title:    "title",
+		Priority: HighPriority,
+		Sound:    SirenSound,
+		Devices:  []string{"iphone"},
+		URL:      "https://example.com",
+		URLTitle: "example",
+		HTML:     true,
+	}
+	assert.Equal(t, expected, m)
+
+	_, err = b.Device("my iphone").Build()
+	require.EqualError(t, err, `pushover: invalid device name "my iphone"`)
+	assert.Equal(t, []string{"iphone"}, m.Devices)
+
+	_, err = NewMessage(testUser, "text").Emergency(10, 0, "").Build()
+	require.EqualError(t, err, "pushover: emergency retry is too small: 10 seconds (min 30)")
+}
@@ -0,0 +1,84 @@
+package pushover
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CallbackPayload represents data sent by Pushover to the emergency message callback URL
+// when the message is acknowledged.
+//
+// See https://pushover.net/api/receipts#callback.
+type CallbackPayload struct {
+	Receipt              string    // receipt of acknowledged message
+	Acknowledged         bool      // true if message was acknowledged
+	AcknowledgedAt       time.Time // acknowledgement time
+	AcknowledgedBy       string    // user key of the user that acknowledged message
+	AcknowledgedByDevice string    // device name of the user that acknowledged message
+}
+
+// ParseCallback parses Pushover callback request.
+func ParseCallback(req *http.Request) (*CallbackPayload, error) {
+	if req.Method != "POST" {
+		return nil, fmt.Errorf("pushover: unexpected callback method %s", req.Method)
+	}
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	receipt := req.PostForm.Get("receipt")
+	if receipt == "" {
+		return nil, fmt.Errorf("pushover: callback without receipt")
+	}
+
+	p := &CallbackPayload{
+		Receipt:              receipt,
+		Acknowledged:         req.PostForm.Get("acknowledged") == "1",
+		AcknowledgedBy:       req.PostForm.Get("acknowledged_by"),
+		AcknowledgedByDevice: req.PostForm.Get("acknowledged_by_device"),
+	}
+
+	if s := req.PostForm.Get("acknowledged_at"); s != "" {
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("pushover: invalid callback acknowledged_at %q: %s", s, err)
+		}
+		p.AcknowledgedAt = time.Unix(sec, 0)
+	}
+
+	return p, nil
+}
+
+// CallbackHandler returns http.Handler that parses Pushover callback requests and calls f for each of them.
+// f is called synchronously; response is sent after it returns.
+func CallbackHandler(f func(*CallbackPayload)) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		p, err := ParseCallback(req)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f(p)
+	})
+}
+
+// CallbackChannelHandler returns http.Handler that parses Pushover callback requests and sends them to ch.
+// If ch is not ready to receive before the request is canceled, 503 Service Unavailable is returned.
+func CallbackChannelHandler(ch chan<- *CallbackPayload) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		p, err := ParseCallback(req)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case ch <- p:
+		case <-req.Context().Done():
+			http.Error(rw, req.Context().Err().Error(), http.StatusServiceUnavailable)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package pushover
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCallbackRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/callback", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestParseCallback(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		req := newCallbackRequest(url.Values{
+			"receipt":                {"rLqVuqTRh62UzxtmqiaLzQmVcPgiCy"},
+			"acknowledged":           {"1"},
+			"acknowledged_at":        {"1360019238"},
+			"acknowledged_by":        {"uQiRzpo4DXghDmr9QzzfQu27cmVRsG"},
+			"acknowledged_by_device": {"iphone"},
+		})
+		p, err := ParseCallback(req)
+		require.NoError(t, err)
+		expected := &CallbackPayload{
+			Receipt:              "rLqVuqTRh62UzxtmqiaLzQmVcPgiCy",
+			Acknowledged:         true,
+			AcknowledgedAt:       time.Unix(1360019238, 0),
+			AcknowledgedBy:       "uQiRzpo4DXghDmr9QzzfQu27cmVRsG",
+			AcknowledgedByDevice: "iphone",
+		}
+		assert.Equal(t, expected, p)
+	})
+
+	t.Run("NoReceipt", func(t *testing.T) {
+		_, err := ParseCallback(newCallbackRequest(url.Values{"acknowledged": {"1"}}))
+		require.Error(t, err)
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		_, err := ParseCallback(httptest.NewRequest("GET", "/callback?receipt=r", nil))
+		require.Error(t, err)
+	})
+}
+
+func TestCallbackChannelHandler(t *testing.T) {
+	ch := make(chan *CallbackPayload, 1)
+	h := CallbackChannelHandler(ch)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newCallbackRequest(url.Values{"receipt": {"r"}, "acknowledged": {"1"}}))
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, &CallbackPayload{Receipt: "r", Acknowledged: true}, <-ch)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newCallbackRequest(url.Values{}))
+	assert.Equal(t, 400, rec.Code)
+}
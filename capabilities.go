@@ -0,0 +1,34 @@
+package pushover
+
+// APICapabilities describes Pushover API endpoints and limits supported by this package version.
+type APICapabilities struct {
+	Endpoints []string       `json:"endpoints"` // default API endpoint URLs
+	Limits    map[string]int `json:"limits"`    // limits checked or applied by the client
+}
+
+// Capabilities returns Pushover API endpoints and limits supported by this package version.
+// It is intended for tools that need feature detection.
+func Capabilities() *APICapabilities {
+	return &APICapabilities{
+		Endpoints: []string{
+			DefaultBaseURL + messagesPath,
+			DefaultBaseURL + validatePath,
+			DefaultBaseURL + glancesPath,
+			DefaultBaseURL + receiptsPath + "{receipt}.json",
+		},
+		Limits: map[string]int{
+			"message_length":        MaxMessageLength,
+			"title_length":          MaxTitleLength,
+			"url_length":            MaxURLLength,
+			"url_title_length":      MaxURLTitleLength,
+			"users_per_request":     MaxUsersPerRequest,
+			"attachment_bytes":      MaxAttachmentBytes,
+			"emergency_retry_min":   MinEmergencyRetry,
+			"emergency_expire_max":  MaxEmergencyExpire,
+			"glance_title_length":   maxGlanceTitleLength,
+			"glance_text_length":    maxGlanceTextLength,
+			"glance_subtext_length": maxGlanceSubtextLength,
+			"glance_percent":        maxGlancePercent,
+		},
+	}
+}
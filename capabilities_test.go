@@ -0,0 +1,37 @@
+package pushover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	actual := Capabilities()
+
+	assert.Equal(t, []string{
+		DefaultBaseURL + "messages.json",
+		DefaultBaseURL + "users/validate.json",
+		DefaultBaseURL + "glances.json",
+		DefaultBaseURL + "receipts/{receipt}.json",
+	}, actual.Endpoints)
+
+	assert.Equal(t, map[string]int{
+		"message_length":        MaxMessageLength,
+		"title_length":          MaxTitleLength,
+		"url_length":            MaxURLLength,
+		"url_title_length":      MaxURLTitleLength,
+		"users_per_request":     MaxUsersPerRequest,
+		"attachment_bytes":      MaxAttachmentBytes,
+		"emergency_retry_min":   MinEmergencyRetry,
+		"emergency_expire_max":  MaxEmergencyExpire,
+		"glance_title_length":   maxGlanceTitleLength,
+		"glance_text_length":    maxGlanceTextLength,
+		"glance_subtext_length": maxGlanceSubtextLength,
+		"glance_percent":        maxGlancePercent,
+	}, actual.Limits)
+
+	// callers may modify returned value
+	actual.Limits["message_length"] = 0
+	assert.Equal(t, MaxMessageLength, Capabilities().Limits["message_length"])
+}
@@ -0,0 +1,113 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned (wrapped in TemporaryError) when request is not sent
+// because circuit breaker is open, see WithCircuitBreaker.
+var ErrCircuitOpen = errors.New("pushover: circuit breaker is open")
+
+// CircuitState represents circuit breaker state.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	CircuitClosed   CircuitState = iota // requests are sent
+	CircuitOpen                         // requests fail fast
+	CircuitHalfOpen                     // a single probe request is sent
+)
+
+var circuitStateNames = map[CircuitState]string{
+	CircuitClosed:   "closed",
+	CircuitOpen:     "open",
+	CircuitHalfOpen: "half-open",
+}
+
+// String returns state name.
+func (s CircuitState) String() string {
+	if n, ok := circuitStateNames[s]; ok {
+		return n
+	}
+	return strconv.Itoa(int(s))
+}
+
+// circuitBreaker counts consecutive failures and stops requests after threshold is reached.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	onChange  func(from, to CircuitState)
+
+	m        sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow returns true if request may be sent at given time.
+// In half-open state, only one probe request is allowed at a time.
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// record records request result at given time.
+// Only temporary failures other than rate limiting are counted; anything else means API is up.
+// Requests canceled by the caller (ctx is done) are not counted at all.
+func (b *circuitBreaker) record(ctx context.Context, err error, now time.Time) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.probing = false
+
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failed := err != nil && IsRetryable(err) && !isRateLimited(err)
+
+	if !failed {
+		b.failures = 0
+		b.setState(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.openedAt = now
+		b.setState(CircuitOpen)
+	}
+}
+
+// setState changes state and calls state change function. b.m should be held.
+func (b *circuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(from, state)
+	}
+}
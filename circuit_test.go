@@ -0,0 +1,87 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var requests, down int32 = 0, 1
+	var changes []string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			rw.WriteHeader(503)
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithCircuitBreaker(2, 50*time.Millisecond, func(from, to CircuitState) {
+		changes = append(changes, from.String()+"->"+to.String())
+	}))
+
+	ctx := context.Background()
+
+	require.Error(t, c.Send(ctx, testUser, "message"))
+	require.Error(t, c.Send(ctx, testUser, "message"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// fails fast
+	err := c.Send(ctx, testUser, "message")
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	var temporary *TemporaryError
+	assert.True(t, errors.As(err, &temporary))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// failed probe
+	time.Sleep(60 * time.Millisecond)
+	require.Error(t, c.Send(ctx, testUser, "message"))
+	assert.True(t, errors.Is(c.Send(ctx, testUser, "message"), ErrCircuitOpen))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// successful probe
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, c.Send(ctx, testUser, "message"))
+	require.NoError(t, c.Send(ctx, testUser, "message"))
+
+	expected := []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}
+	assert.Equal(t, expected, changes)
+}
+
+func TestCircuitBreakerIgnoresFatalErrors(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(400)
+		rw.Write([]byte(`{"user":"invalid","errors":["user identifier is invalid"],"status":0,"request":"r"}`))
+	}, WithCircuitBreaker(1, time.Minute, nil))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		err := c.Send(ctx, testUser, "message")
+		assert.True(t, errors.Is(err, ErrInvalidUser))
+	}
+}
+
+func TestCircuitBreakerIgnoresCanceled(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithCircuitBreaker(1, time.Minute, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.Send(ctx, testUser, "message")
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+
+	require.NoError(t, c.Send(context.Background(), testUser, "message"))
+}
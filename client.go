@@ -4,50 +4,59 @@ package pushover
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
-// Message priority.
+// DefaultBaseURL is the default Pushover API base URL.
+const DefaultBaseURL = "https://api.pushover.net/1/"
+
+// DefaultUserAgent is the default User-Agent header value.
+const DefaultUserAgent = "github.com/AlekSi/pushover"
+
+// API endpoints, relative to base URL.
+const (
+	messagesPath = "messages.json"
+	validatePath = "users/validate.json"
+	glancesPath  = "glances.json"
+	receiptsPath = "receipts/"
+)
+
+// Response size limits.
+const (
+	maxResponseSize  = 1 << 20 // read at most that many bytes of response body
+	maxErrorBodySize = 4096    // retain at most that many bytes of response body in errors
+)
+
+// Message limits enforced by Pushover API and checked by Message.Validate.
 const (
-	LowestPriority    = -2 // lowest priority, no notification
-	LowPriority       = -1 // low priority, no sound and vibration
-	NormalPriority    = 0  // normal priority, default
-	HighPriority      = 1  // high priority, always with sound and vibration
-	EmergencyPriority = 2  // emergency priority, requires acknowledge
+	MaxMessageLength   = 1024    // characters in message text
+	MaxTitleLength     = 250     // characters in message title
+	MaxURLLength       = 512     // characters in supplementary URL
+	MaxURLTitleLength  = 100     // characters in supplementary URL title
+	MaxUsersPerRequest = 50      // comma-separated user keys in one message
+	MaxAttachmentBytes = 5242880 // attachment size
+
+	MinEmergencyRetry  = 30    // seconds between emergency notification retries
+	MaxEmergencyExpire = 10800 // seconds of emergency notification retries
 )
 
-// Message sound.
+// Emergency priority defaults.
 const (
-	PushoverSound     = "pushover" // default
-	BikeSound         = "bike"
-	BugleSound        = "bugle"
-	CashregisterSound = "cashregister"
-	ClassicalSound    = "classical"
-	CosmicSound       = "cosmic"
-	FallingSound      = "falling"
-	GamelanSound      = "gamelan"
-	IncomingSound     = "incoming"
-	IntermissionSound = "intermission"
-	MagicSound        = "magic"
-	MechanicalSound   = "mechanical"
-	PianobarSound     = "pianobar"
-	SirenSound        = "siren"
-	SpacealarmSound   = "spacealarm"
-	TugboatSound      = "tugboat"
-	AlienSound        = "alien"
-	ClimbSound        = "climb"
-	PersistentSound   = "persistent"
-	EchoSound         = "echo"
-	UpdownSound       = "updown"
-	VibrateSound      = "vibrate" // vibrate only
-	NoneSound         = "none"    // silent
+	defaultEmergencyRetry  = 60
+	defaultEmergencyExpire = 3600
 )
 
 // Message to send.
@@ -57,39 +66,197 @@ type Message struct {
 	Message string // message to send
 
 	// optional parameters
+	Token     string    // application token overriding the client's one
 	Devices   []string  // device names to send the message directly to that devices, rather than all of the user's devices
 	Title     string    // message title, defaults to application name
 	URL       string    // supplementary URL
 	URLTitle  string    // title for supplementary URL
-	Priority  int       // priority, defaults to NormalPriority
-	Sound     string    // message sound
+	Priority  Priority  // priority, defaults to NormalPriority
+	Sound     Sound     // message sound
 	Timestamp time.Time // message time
 	HTML      bool      // enable HTML formatting
 	Monospace bool      // enable monospace messages
 
 	// for emergency priority only
-	Retry    int
-	Expire   int
-	Callback string
+	Retry    int      // how often (in seconds) to retry notification, defaults to 60, at least 30
+	Expire   int      // how long (in seconds) to retry notification, defaults to 3600, at most 10800
+	Callback string   // URL for acknowledgement callback, see ParseCallback
+	Tags     []string // tags for cancelling retries and deduplication, see WithEmergencyDeduplication
+
+	// image attachment, streamed without buffering as a whole; at most MaxAttachmentBytes
+	Attachment     io.Reader `json:"-"` // rewound after each send if it implements io.Seeker, otherwise read once
+	AttachmentName string    // attachment file name, defaults to "attachment"
+	AttachmentType string    // attachment MIME type, such as "image/jpeg"
+
+	// additional API parameters, override parameters set from other fields
+	Extra url.Values
+}
+
+// Clone returns a deep copy of the message.
+func (m *Message) Clone() *Message {
+	res := *m
+	if m.Devices != nil {
+		res.Devices = append([]string(nil), m.Devices...)
+	}
+	if m.Tags != nil {
+		res.Tags = append([]string(nil), m.Tags...)
+	}
+	if m.Extra != nil {
+		res.Extra = make(url.Values, len(m.Extra))
+		for k, v := range m.Extra {
+			res.Extra[k] = append([]string(nil), v...)
+		}
+	}
+	return &res
+}
+
+// ForDevices returns copies of the message, one for each of given devices.
+// Returned messages can be modified independently, for example, to use different sounds.
+func (m *Message) ForDevices(devices ...string) []*Message {
+	res := make([]*Message, len(devices))
+	for i, d := range devices {
+		res[i] = m.Clone()
+		res[i].Devices = []string{d}
+	}
+	return res
+}
+
+var (
+	userKeyRE    = regexp.MustCompile(`^[A-Za-z0-9]{30}$`)
+	deviceNameRE = regexp.MustCompile(`^[A-Za-z0-9_-]{1,25}$`)
+)
+
+// IsValidUserKey returns true if s has a valid format of user or group key.
+// It does not check that key actually exists; see Client.ValidateRecipient.
+func IsValidUserKey(s string) bool {
+	return userKeyRE.MatchString(s)
+}
+
+// IsValidDeviceName returns true if s has a valid format of device name.
+func IsValidDeviceName(s string) bool {
+	return deviceNameRE.MatchString(s)
+}
+
+// Validate checks message fields before sending.
+// Zero emergency Retry and Expire values are valid; defaults are used for them.
+func (m *Message) Validate() error {
+	users := strings.Split(m.User, ",")
+	if len(users) > MaxUsersPerRequest {
+		return fmt.Errorf("pushover: too many user keys: %d (max %d)", len(users), MaxUsersPerRequest)
+	}
+	for _, u := range users {
+		if !IsValidUserKey(u) {
+			return fmt.Errorf("pushover: invalid user key %q", u)
+		}
+	}
+	for _, d := range m.Devices {
+		if !IsValidDeviceName(d) {
+			return fmt.Errorf("pushover: invalid device name %q", d)
+		}
+	}
+
+	if l := utf8.RuneCountInString(m.Message); l > MaxMessageLength {
+		return fmt.Errorf("%w: %d characters (max %d)", ErrMessageTooLong, l, MaxMessageLength)
+	}
+	for _, f := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"title", m.Title, MaxTitleLength},
+		{"URL", m.URL, MaxURLLength},
+		{"URL title", m.URLTitle, MaxURLTitleLength},
+	} {
+		if l := utf8.RuneCountInString(f.value); l > f.max {
+			return fmt.Errorf("pushover: message %s is too long: %d characters (max %d)", f.name, l, f.max)
+		}
+	}
+
+	if m.Attachment != nil {
+		if size := attachmentSize(m.Attachment); size > MaxAttachmentBytes {
+			return fmt.Errorf("pushover: attachment is too large: %d bytes (max %d)", size, MaxAttachmentBytes)
+		}
+	}
+
+	if !m.Priority.IsValid() {
+		return fmt.Errorf("pushover: invalid priority %d", m.Priority)
+	}
+	if m.Priority == EmergencyPriority {
+		if m.Retry != 0 && m.Retry < MinEmergencyRetry {
+			return fmt.Errorf("pushover: emergency retry is too small: %d seconds (min %d)", m.Retry, MinEmergencyRetry)
+		}
+		if m.Expire < 0 || m.Expire > MaxEmergencyExpire {
+			return fmt.Errorf("pushover: emergency expire is invalid: %d seconds (max %d)", m.Expire, MaxEmergencyExpire)
+		}
+	}
+
+	return nil
 }
 
 // Client represents Pushover API client.
 //
 // See https://pushover.net/api.
 type Client struct {
-	appToken string
+	softFailures uint64 // first for atomic alignment
+	clockSkew    int64  // server time minus local time, in nanoseconds
+
+	appToken       string
+	defaultUser    string
+	baseURL        string
+	userAgent      string
+	timeout        time.Duration
+	logger         *slog.Logger
+	prioritySounds map[Priority]Sound
+	jsonBody       bool
+	truncate       bool
+	softFail       *log.Logger
+
+	emergencyDowngrade func(message *Message, err error)
+	emergencyDedup     bool
+	resolver           RecipientResolver
+	connRetry          func(err error) bool
+	attemptTimeout     time.Duration
+	skewCorrection     bool
+	requestHooks       []func(*http.Request)
+	responseHooks      []func(*http.Response, error)
+	debugDump          io.Writer
+	dumpM              sync.Mutex // serializes writes to debugDump
 
 	m          sync.RWMutex
 	httpClient *http.Client
+
+	receiptsM sync.Mutex
+	receipts  map[string]string // outstanding emergency message receipts by token, recipient, and tag
+
+	dedupWindow time.Duration
+	dedupKey    func(*Message) string
+	dedupM      sync.Mutex
+	dedupSeen   map[string]time.Time // deduplication key -> first send time
+
+	quotaThreshold int
+	quotaWarning   func(Limits)
+	limitsM        sync.Mutex
+	limits         map[string]Limits // token -> limits from the last response with limit headers
+	quotaWarned    map[string]bool   // token -> warning was sent
+
+	breaker *circuitBreaker
 }
 
-// NewClient creates new client.
-func NewClient(appToken string) (*Client, error) {
-	return &Client{
-		appToken: appToken,
-	}, nil
+// NewClient creates new client with given options.
+func NewClient(appToken string, opts ...Option) (*Client, error) {
+	c := &Client{
+		appToken:  appToken,
+		baseURL:   DefaultBaseURL,
+		userAgent: DefaultUserAgent,
+		connRetry: IsConnectionError,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c, nil
 }
 
+// SetHTTPClient sets HTTP client used for requests; see also WithHTTPClient.
 func (c *Client) SetHTTPClient(client *http.Client) {
 	c.m.Lock()
 	defer c.m.Unlock()
@@ -107,48 +274,289 @@ func (c *Client) http() *http.Client {
 	return http.DefaultClient
 }
 
-func (c *Client) sendRequest(ctx context.Context, URL string, data string) error {
-	// prepare request
-	body := strings.NewReader(data)
-	req, err := http.NewRequestWithContext(ctx, "POST", URL, body)
+// encodeRequest returns request body and its content type for given data.
+func (c *Client) encodeRequest(data url.Values) (string, string, error) {
+	if !c.jsonBody {
+		return data.Encode(), "application/x-www-form-urlencoded", nil
+	}
+
+	m := make(map[string]string, len(data))
+	for k := range data {
+		m[k] = data.Get(k)
+	}
+	b, err := json.Marshal(m)
 	if err != nil {
-		return err
+		return "", "", err
+	}
+	return string(b), "application/json", nil
+}
+
+// sendRequest sends API request, returning either successful response,
+// or error wrapped in TemporaryError or FatalError.
+// Retryable errors are retried as configured by send options.
+func (c *Client) sendRequest(ctx context.Context, method, path string, data url.Values, o sendOptions) (*Response, error) {
+	backoff := defaultBackoff
+	if o.backoff != nil {
+		backoff = *o.backoff
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "github.com/AlekSi/pushover")
+	retryable := IsRetryable
+	if o.retryable != nil {
+		retryable = o.retryable
+	}
+
+	start := time.Now()
+	for i := 0; ; i++ {
+		if c.breaker != nil && !c.breaker.allow(time.Now()) {
+			return nil, &TemporaryError{Err: ErrCircuitOpen}
+		}
 
-	// do request and read body
-	resp, err := c.http().Do(req)
+		res, err := c.doRequest(ctx, method, path, data, o)
+		if c.breaker != nil {
+			c.breaker.record(ctx, err, time.Now())
+		}
+		if err = wrapError(err); err == nil || !retryable(err) || i >= o.retries {
+			return res, err
+		}
+
+		delay := backoff.Delay(i)
+		if backoff.MaxElapsed > 0 && time.Since(start)+delay > backoff.MaxElapsed {
+			return res, err
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, err
+		case <-t.C:
+		}
+	}
+}
+
+func (c *Client) doRequest(ctx context.Context, method, path string, data url.Values, o sendOptions) (*Response, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// prepare request; GET requests pass data in query.
+	// URL without query is used in errors and logs, so the token is never exposed.
+	URL := c.baseURL + path
+	reqURL := URL
+	var encoded, contentType string
+	switch {
+	case method == "GET":
+		reqURL += "?" + data.Encode()
+	case o.attachment != nil:
+		// multipart body is created for each attempt
+	default:
+		var err error
+		if encoded, contentType, err = c.encodeRequest(data); err != nil {
+			return nil, err
+		}
+	}
+	// do request with per-attempt timeout and read body
+	attempt := func(fresh bool) (*http.Response, []byte, error) {
+		attemptCtx := ctx
+		attemptTimeout := c.attemptTimeout
+		if o.attemptTimeout > 0 {
+			attemptTimeout = o.attemptTimeout
+		}
+		if attemptTimeout > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(ctx, attemptTimeout)
+			defer cancel()
+		}
+
+		var body io.Reader
+		ct := contentType
+		switch {
+		case o.attachment != nil:
+			rc, multipartCT, err := o.attachment.multipart(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			defer rc.Close()
+			body, ct = rc, multipartCT
+		case ct != "":
+			body = strings.NewReader(encoded)
+		}
+		req, err := http.NewRequestWithContext(attemptCtx, method, reqURL, body)
+		if err != nil {
+			hideQuery(err, URL)
+			return nil, nil, err
+		}
+		if ct != "" {
+			req.Header.Set("Content-Type", ct)
+		}
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Close = fresh
+		for _, h := range c.requestHooks {
+			h(req)
+		}
+		if c.debugDump != nil {
+			c.dumpRequest(req, data.Get("token"))
+		}
+
+		resp, err := c.http().Do(req)
+		hideQuery(err, URL)
+		for _, h := range c.responseHooks {
+			h(resp, err)
+		}
+		if err != nil {
+			if o.attachment != nil {
+				if aerr := o.attachment.localErr(); aerr != nil {
+					return nil, nil, aerr
+				}
+			}
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		if c.debugDump != nil {
+			c.dumpResponse(resp, b, data.Get("token"))
+		}
+		return resp, b, err
+	}
+
+	// retry connection-level failures once
+	start := time.Now()
+	resp, b, err := attempt(false)
+	var aerr *attachmentError
+	if resp == nil && err != nil && !errors.As(err, &aerr) && c.connRetry != nil && ctx.Err() == nil && c.connRetry(err) {
+		if c.logger != nil {
+			c.logger.WarnContext(ctx, "pushover: retrying after connection error", "url", URL, "error", err)
+		}
+
+		// other idle connections to the same host are likely stale too
+		c.http().CloseIdleConnections()
+		resp, b, err = attempt(true)
+	}
+	if errors.As(err, &aerr) {
+		c.logRequest(ctx, path, data, time.Since(start), nil, aerr)
+		return nil, aerr
+	}
 	if err != nil {
-		return err
+		op := "send"
+		if resp != nil {
+			op = "read"
+		}
+		err = &Error{Op: op, URL: URL, Err: err}
+		c.logRequest(ctx, path, data, time.Since(start), resp, err)
+		return nil, err
+	}
+
+	if c.skewCorrection {
+		c.updateClockSkew(resp.Header.Get("Date"))
+	}
+	c.updateLimits(data.Get("token"), parseLimits(resp.Header))
+
+	d := time.Since(start)
+	res, err := parseResponse(URL, resp.StatusCode, resp.Header, b)
+	if res != nil {
+		res.Start = start
+		res.Duration = d
+		res.Date, _ = http.ParseTime(resp.Header.Get("Date"))
+	}
+	c.logRequest(ctx, path, data, d, resp, err)
+	return res, err
+}
+
+// logRequest logs finished request at debug level.
+// Application token is redacted; resp may be nil.
+func (c *Client) logRequest(ctx context.Context, path string, data url.Values, d time.Duration, resp *http.Response, err error) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("endpoint", path),
+		slog.String("token", redactToken(data.Get("token"))),
+		slog.String("user", data.Get("user")),
+		slog.Duration("duration", d),
+	}
+	if p := data.Get("priority"); p != "" {
+		attrs = append(attrs, slog.String("priority", p))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if l := parseLimits(resp.Header); l.Limit != 0 {
+			attrs = append(attrs, slog.Int("remaining", l.Remaining))
+		}
 	}
-	defer resp.Body.Close()
-	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	if labels := labelsAttrs(ctx); labels != nil {
+		attrs = append(attrs, slog.Attr{Key: "labels", Value: slog.GroupValue(labels...)})
 	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "pushover: request", attrs...)
+}
+
+// hideQuery replaces request URL in *url.Error in err chain with given URL without query that contains token.
+func hideQuery(err error, URL string) {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		uerr.URL = URL
+	}
+}
+
+// redactToken returns token with all but the first 4 characters masked.
+func redactToken(token string) string {
+	if len(token) <= 4 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + strings.Repeat("*", len(token)-4)
+}
 
-	// parse response
-	var jsonOk bool
-	var status float64
-	m := make(map[string]interface{})
-	err = json.Unmarshal(b, &m)
-	if err == nil {
-		status, jsonOk = m["status"].(float64)
+// parseResponse returns successful response or error for given response status code, headers and body.
+func parseResponse(URL string, statusCode int, header http.Header, b []byte) (*Response, error) {
+	var res apiResponse
+	err := json.Unmarshal(b, &res)
+	if statusCode == 200 {
+		if err != nil {
+			return nil, &Error{Op: "decode", URL: URL, Err: err}
+		}
+		if res.Status == 1 {
+			return &res.Response, nil
+		}
 	}
 
-	if resp.StatusCode == 200 && jsonOk && status == 1.0 {
-		return nil
+	// do not retain large bodies (such as HTML error pages from proxies)
+	if len(b) > maxErrorBodySize {
+		b = append([]byte(nil), b[:maxErrorBodySize]...)
 	}
 
-	return fmt.Errorf("%d: %s", resp.StatusCode, b)
+	apiErr := &APIError{
+		HTTPStatus: statusCode,
+		Messages:   res.Errors,
+		Fields:     res.fields(),
+		RequestID:  res.Request,
+		body:       b,
+	}
+	if statusCode == http.StatusTooManyRequests {
+		return nil, &RateLimitError{
+			Limits: parseLimits(header),
+			Err:    apiErr,
+		}
+	}
+	return nil, apiErr
 }
 
-func (c *Client) makeMessageData(message *Message) string {
+// messageToken returns application token used to send given message.
+func (c *Client) messageToken(message *Message) string {
+	if message.Token != "" {
+		return message.Token
+	}
+	return c.appToken
+}
+
+func (c *Client) makeMessageData(message *Message) url.Values {
 	data := make(url.Values)
 
 	// set required parameters
-	data.Set("token", c.appToken)
+	data.Set("token", c.messageToken(message))
 	data.Set("user", message.User)
 	data.Set("message", message.Message)
 
@@ -166,13 +574,17 @@ func (c *Client) makeMessageData(message *Message) string {
 		data.Set("url_title", message.URLTitle)
 	}
 	if message.Priority != 0 {
-		data.Set("priority", strconv.Itoa(message.Priority))
+		data.Set("priority", strconv.Itoa(int(message.Priority)))
 	}
-	if message.Sound != "" {
-		data.Set("sound", message.Sound)
+	sound := message.Sound
+	if sound == "" {
+		sound = c.prioritySounds[message.Priority]
+	}
+	if sound != "" {
+		data.Set("sound", string(sound))
 	}
 	if !message.Timestamp.IsZero() {
-		data.Set("timestamp", strconv.FormatInt(message.Timestamp.Unix(), 10))
+		data.Set("timestamp", strconv.FormatInt(message.Timestamp.Add(c.ClockSkew()).Unix(), 10))
 	}
 	if message.HTML {
 		data.Set("html", "1")
@@ -183,85 +595,341 @@ func (c *Client) makeMessageData(message *Message) string {
 
 	// set parameters for emergency priority
 	if message.Priority == EmergencyPriority {
-		data.Set("retry", strconv.Itoa(message.Retry))
-		data.Set("expire", strconv.Itoa(message.Expire))
+		retry, expire := message.Retry, message.Expire
+		if retry == 0 {
+			retry = defaultEmergencyRetry
+		}
+		if expire == 0 {
+			expire = defaultEmergencyExpire
+		}
+		data.Set("retry", strconv.Itoa(retry))
+		data.Set("expire", strconv.Itoa(expire))
 		if message.Callback != "" {
 			data.Set("callback", message.Callback)
 		}
+		if len(message.Tags) != 0 {
+			data.Set("tags", strings.Join(message.Tags, ","))
+		}
 	}
 
-	return data.Encode()
+	// set additional parameters
+	for k, v := range message.Extra {
+		data[k] = v
+	}
+
+	return data
+}
+
+// downgradeMessage returns a copy of emergency message with high priority and without emergency parameters.
+func downgradeMessage(message *Message) *Message {
+	m := *message
+	m.Priority = HighPriority
+	m.Retry, m.Expire, m.Callback, m.Tags = 0, 0, "", nil
+	return &m
+}
+
+// sendDowngraded sends emergency message that was rejected with given error with high priority instead.
+// On success, it calls emergency downgrade function and returns nil error;
+// otherwise, it returns the original error.
+func (c *Client) sendDowngraded(ctx context.Context, message *Message, err error, o sendOptions) (*Response, error) {
+	res, err2 := c.sendRequest(ctx, "POST", messagesPath, c.makeMessageData(downgradeMessage(message)), o)
+	if err2 != nil {
+		return nil, err
+	}
+
+	if c.logger != nil {
+		c.logger.WarnContext(ctx, "pushover: emergency message sent with high priority", "error", err)
+	}
+	c.emergencyDowngrade(message, err)
+	return res, nil
+}
+
+// truncateMessage returns a copy of the message with text fields truncated to Pushover limits.
+func truncateMessage(message *Message) *Message {
+	m := *message
+	m.Message = truncate(m.Message, MaxMessageLength)
+	m.Title = truncate(m.Title, MaxTitleLength)
+	m.URL = truncate(m.URL, MaxURLLength)
+	m.URLTitle = truncate(m.URLTitle, MaxURLTitleLength)
+	return &m
+}
+
+// truncate returns s truncated to max characters, with the last one replaced by ellipsis.
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	r := []rune(s)
+	return string(r[:max-1]) + "…"
 }
 
 // SendMessage sends given message.
-func (c *Client) SendMessage(ctx context.Context, message *Message) error {
-	return c.sendRequest(ctx, "https://api.pushover.net/1/messages.json", c.makeMessageData(message))
+func (c *Client) SendMessage(ctx context.Context, message *Message, opts ...SendOption) error {
+	_, err := c.SendMessageWithResponse(ctx, message, opts...)
+	return err
+}
+
+// SendMessageWithResponse sends given message and returns API response.
+// If error is suppressed by soft-fail mode, both returned values are nil.
+func (c *Client) SendMessageWithResponse(ctx context.Context, message *Message, opts ...SendOption) (*Response, error) {
+	o := newSendOptions(opts)
+	if o.attachment = newAttachment(message); o.attachment != nil {
+		defer o.attachment.reset()
+	}
+
+	if message.User == "" && c.defaultUser != "" {
+		m := *message
+		m.User = c.defaultUser
+		message = &m
+	}
+	if c.resolver != nil {
+		m, err := c.resolveRecipient(ctx, message)
+		if err != nil {
+			return nil, c.handleSoftFail("message", wrapError(err))
+		}
+		message = m
+	}
+	if c.truncate {
+		message = truncateMessage(message)
+	}
+	if suffix := labelsSuffix(ctx, message.HTML); suffix != "" {
+		message = appendLabels(message, suffix)
+	}
+
+	if !o.skipValidation {
+		if err := message.Validate(); err != nil {
+			err = &FatalError{Err: err}
+
+			// invalid emergency parameters are dropped by downgrade
+			if c.emergencyDowngrade != nil && message.Priority == EmergencyPriority && downgradeMessage(message).Validate() == nil {
+				res, err2 := c.sendDowngraded(ctx, message, err, o)
+				return res, c.handleSoftFail("message", err2)
+			}
+			return nil, c.handleSoftFail("message", err)
+		}
+	}
+
+	var dedupKey string
+	if c.dedupWindow > 0 {
+		dedupKey = c.dedupKey(message)
+		if !c.dedupCheck(dedupKey, time.Now()) {
+			return nil, c.handleSoftFail("message", &FatalError{Err: ErrDuplicateSuppressed})
+		}
+	}
+
+	dedup := c.emergencyDedup && message.Priority == EmergencyPriority && len(message.Tags) != 0
+	if dedup {
+		if res := c.outstandingEmergency(ctx, message); res != nil {
+			if c.logger != nil {
+				c.logger.InfoContext(ctx, "pushover: duplicate emergency message suppressed", "receipt", res.Receipt)
+			}
+			return res, nil
+		}
+	}
+
+	res, err := c.sendRequest(ctx, "POST", messagesPath, c.makeMessageData(message), o)
+	if err == nil && dedup && res.Receipt != "" {
+		c.trackEmergency(message, res.Receipt)
+	}
+	if err != nil && c.emergencyDowngrade != nil && message.Priority == EmergencyPriority && isFatal(err) {
+		if res2, err2 := c.sendDowngraded(ctx, message, err, o); err2 == nil {
+			res, err = res2, nil
+		}
+	}
+	if err != nil && c.dedupWindow > 0 {
+		c.dedupForget(dedupKey)
+	}
+	return res, c.handleSoftFail("message", err)
+}
+
+// handleSoftFail logs and counts err and returns nil if soft-fail mode is enabled.
+// Otherwise, it returns err as is.
+func (c *Client) handleSoftFail(what string, err error) error {
+	if err == nil || c.softFail == nil {
+		return err
+	}
+
+	atomic.AddUint64(&c.softFailures, 1)
+	c.softFail.Printf("failed to send %s: %s", what, err)
+	return nil
+}
+
+// SoftFailures returns the number of failures suppressed by soft-fail mode.
+func (c *Client) SoftFailures() uint64 {
+	return atomic.LoadUint64(&c.softFailures)
 }
 
 // Send is a shortcut for sending a basic message to given user.
-func (c *Client) Send(ctx context.Context, user, message string) error {
+func (c *Client) Send(ctx context.Context, user, message string, opts ...SendOption) error {
 	m := &Message{
 		User:    user,
 		Message: message,
 	}
-	return c.SendMessage(ctx, m)
+	return c.SendMessage(ctx, m, opts...)
+}
+
+// ValidateRecipient checks that given user or group key is valid and has at least one active device.
+// Returned error matches ErrInvalidToken, ErrInvalidUser or ErrNoActiveDevices (with errors.Is) for those cases.
+func (c *Client) ValidateRecipient(ctx context.Context, user string) error {
+	data := make(url.Values)
+	data.Set("token", c.appToken)
+	data.Set("user", user)
+
+	_, err := c.sendRequest(ctx, "POST", validatePath, data, sendOptions{})
+	return err
 }
 
 type Glance struct {
 	User string
 
-	Device string
+	Devices []string // device names to update glance on only that devices, rather than all of the user's devices
 
-	// optional parameters
+	// optional parameters; nil values are not sent, use Clear methods to remove values
 	Title   *string
 	Text    *string
 	Subtext *string
 	Count   *int
 	Percent *uint
+
+	cleared glanceField
+}
+
+// glanceField is a bit set of glance fields.
+type glanceField uint8
+
+const (
+	glanceTitle glanceField = 1 << iota
+	glanceText
+	glanceSubtext
+	glanceCount
+	glancePercent
+)
+
+// ClearTitle removes glance title.
+func (g *Glance) ClearTitle() {
+	g.Title = nil
+	g.cleared |= glanceTitle
 }
 
+// ClearText removes glance text.
+func (g *Glance) ClearText() {
+	g.Text = nil
+	g.cleared |= glanceText
+}
+
+// ClearSubtext removes glance subtext.
+func (g *Glance) ClearSubtext() {
+	g.Subtext = nil
+	g.cleared |= glanceSubtext
+}
+
+// ClearCount removes glance count.
+func (g *Glance) ClearCount() {
+	g.Count = nil
+	g.cleared |= glanceCount
+}
+
+// ClearPercent removes glance percent.
+func (g *Glance) ClearPercent() {
+	g.Percent = nil
+	g.cleared |= glancePercent
+}
+
+// RemoveCount and RemovePercent, when used as Glance.Count and Glance.Percent, remove glance count and percent.
+//
+// Deprecated: use Glance.ClearCount and Glance.ClearPercent instead.
 var (
 	RemoveCount   = new(int)
 	RemovePercent = new(uint)
 )
 
-func (c *Client) makeGlanceData(glance *Glance) string {
+// Glance limits.
+const (
+	maxGlanceTitleLength   = 100
+	maxGlanceTextLength    = 100
+	maxGlanceSubtextLength = 100
+	maxGlancePercent       = 100
+)
+
+// Validate checks glance fields against Pushover limits.
+func (g *Glance) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"title", g.Title, maxGlanceTitleLength},
+		{"text", g.Text, maxGlanceTextLength},
+		{"subtext", g.Subtext, maxGlanceSubtextLength},
+	} {
+		if f.value == nil {
+			continue
+		}
+		if l := utf8.RuneCountInString(*f.value); l > f.max {
+			return fmt.Errorf("pushover: glance %s is too long: %d characters (max %d)", f.name, l, f.max)
+		}
+	}
+
+	if g.Percent != nil && g.Percent != RemovePercent && *g.Percent > maxGlancePercent {
+		return fmt.Errorf("pushover: glance percent is too large: %d (max %d)", *g.Percent, maxGlancePercent)
+	}
+
+	return nil
+}
+
+func (c *Client) makeGlanceData(glance *Glance) url.Values {
 	data := make(url.Values)
 
 	data.Set("token", c.appToken)
 	data.Set("user", glance.User)
 
-	if glance.Device != "" {
-		data.Set("device", glance.Device)
+	if len(glance.Devices) != 0 {
+		data.Set("device", strings.Join(glance.Devices, ","))
 	}
 
 	if glance.Title != nil {
 		data.Set("title", *glance.Title)
+	} else if glance.cleared&glanceTitle != 0 {
+		data.Set("title", "")
 	}
 	if glance.Text != nil {
 		data.Set("text", *glance.Text)
+	} else if glance.cleared&glanceText != 0 {
+		data.Set("text", "")
 	}
 	if glance.Subtext != nil {
 		data.Set("subtext", *glance.Subtext)
+	} else if glance.cleared&glanceSubtext != 0 {
+		data.Set("subtext", "")
 	}
-	if glance.Count != nil {
-		var count string
-		if glance.Count != RemoveCount {
-			count = fmt.Sprint(*glance.Count)
-		}
-		data.Set("count", count)
+	if glance.Count != nil && glance.Count != RemoveCount {
+		data.Set("count", fmt.Sprint(*glance.Count))
+	} else if glance.Count == RemoveCount || glance.cleared&glanceCount != 0 {
+		data.Set("count", "")
 	}
-	if glance.Percent != nil {
-		var percent string
-		if glance.Percent != RemovePercent {
-			percent = fmt.Sprint(*glance.Percent)
-		}
-		data.Set("percent", percent)
+	if glance.Percent != nil && glance.Percent != RemovePercent {
+		data.Set("percent", fmt.Sprint(*glance.Percent))
+	} else if glance.Percent == RemovePercent || glance.cleared&glancePercent != 0 {
+		data.Set("percent", "")
 	}
 
-	return data.Encode()
+	return data
 }
 
+// SendGlance sends given glance update.
 func (c *Client) SendGlance(ctx context.Context, glance *Glance) error {
-	return c.sendRequest(ctx, "https://api.pushover.net/1/glances.json", c.makeGlanceData(glance))
+	_, err := c.SendGlanceWithResponse(ctx, glance)
+	return err
+}
+
+// SendGlanceWithResponse sends given glance update and returns API response.
+// If error is suppressed by soft-fail mode, both returned values are nil.
+func (c *Client) SendGlanceWithResponse(ctx context.Context, glance *Glance) (*Response, error) {
+	if err := glance.Validate(); err != nil {
+		return nil, c.handleSoftFail("glance", &FatalError{Err: err})
+	}
+
+	res, err := c.sendRequest(ctx, "POST", glancesPath, c.makeGlanceData(glance), sendOptions{})
+	return res, c.handleSoftFail("glance", err)
 }
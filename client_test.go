@@ -1,12 +1,26 @@
 package pushover
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,3 +61,819 @@ func TestMessageClient(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// testUser is a valid user key for tests that do not use real API.
+const testUser = "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"
+
+// newTestClient returns a client with given options that sends all requests to a test server with given handler.
+func newTestClient(t *testing.T, h http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+
+	opts = append([]Option{WithBaseURL(s.URL + "/1/")}, opts...)
+	c, err := NewClient("azGDORePK8gMaC0QOYAMyEEuzJnyUi", opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestValidateRecipient(t *testing.T) {
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		body     string
+		expected error
+	}{
+		"Valid": {
+			body: `{"status":1,"group":0,"devices":["iphone"],"request":"r"}`,
+		},
+		"InvalidToken": {
+			body:     `{"token":"invalid","errors":["application token is invalid"],"status":0,"request":"r"}`,
+			expected: ErrInvalidToken,
+		},
+		"InvalidUser": {
+			body:     `{"user":"invalid","errors":["user key is invalid"],"status":0,"request":"r"}`,
+			expected: ErrInvalidUser,
+		},
+		"NoActiveDevices": {
+			body:     `{"user":"valid","errors":["user has no active devices"],"status":0,"request":"r"}`,
+			expected: ErrNoActiveDevices,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "/1/users/validate.json", req.URL.Path)
+				assert.Equal(t, "uQiRzpo4DXghDmr9QzzfQu27cmVRsG", req.FormValue("user"))
+				if tc.expected != nil {
+					rw.WriteHeader(400)
+				}
+				rw.Write([]byte(tc.body))
+			})
+
+			err := c.ValidateRecipient(ctx, "uQiRzpo4DXghDmr9QzzfQu27cmVRsG")
+			if tc.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expected), "%v", err)
+		})
+	}
+}
+
+func TestMakeGlanceData(t *testing.T) {
+	c, err := NewClient("token")
+	require.NoError(t, err)
+
+	g := &Glance{
+		User:    "user",
+		Devices: []string{"watch", "phone"},
+	}
+	data := c.makeGlanceData(g)
+	expected := url.Values{
+		"token":  {"token"},
+		"user":   {"user"},
+		"device": {"watch,phone"},
+	}
+	assert.Equal(t, expected, data)
+}
+
+func TestGlanceValidate(t *testing.T) {
+	title := "title"
+	long := strings.Repeat("ы", 101)
+	percent := uint(100)
+	tooMuch := uint(101)
+
+	require.NoError(t, (&Glance{Title: &title, Percent: &percent}).Validate())
+	require.NoError(t, (&Glance{Percent: RemovePercent}).Validate())
+	require.EqualError(t, (&Glance{Subtext: &long}).Validate(), "pushover: glance subtext is too long: 101 characters (max 100)")
+	require.EqualError(t, (&Glance{Percent: &tooMuch}).Validate(), "pushover: glance percent is too large: 101 (max 100)")
+}
+
+func TestMakeGlanceDataClear(t *testing.T) {
+	c, err := NewClient("token")
+	require.NoError(t, err)
+
+	text := "text"
+	g := &Glance{
+		User:  "user",
+		Title: &text,
+		Text:  &text,
+	}
+	g.ClearTitle()
+	g.ClearCount()
+	g.ClearPercent()
+	data := c.makeGlanceData(g)
+	expected := url.Values{
+		"token":   {"token"},
+		"user":    {"user"},
+		"title":   {""},
+		"text":    {"text"},
+		"count":   {""},
+		"percent": {""},
+	}
+	assert.Equal(t, expected, data)
+}
+
+func TestMakeMessageDataPrioritySounds(t *testing.T) {
+	c, err := NewClient("token", WithPrioritySounds(map[Priority]Sound{
+		EmergencyPriority: SirenSound,
+		LowPriority:       NoneSound,
+	}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		m     *Message
+		sound string
+	}{
+		{&Message{Priority: EmergencyPriority}, "siren"},
+		{&Message{Priority: EmergencyPriority, Sound: BikeSound}, "bike"},
+		{&Message{Priority: LowPriority}, "none"},
+		{&Message{}, ""},
+	} {
+		data := c.makeMessageData(tc.m)
+		assert.Equal(t, tc.sound, data.Get("sound"))
+	}
+}
+
+func TestSendMessageJSON(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		expected := map[string]string{
+			"token":   "azGDORePK8gMaC0QOYAMyEEuzJnyUi",
+			"user":    testUser,
+			"message": "Привет, \"мир\" & <b>",
+		}
+		assert.Equal(t, expected, body)
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithJSON())
+
+	err := c.Send(context.Background(), testUser, "Привет, \"мир\" & <b>")
+	require.NoError(t, err)
+}
+
+func TestMakeMessageDataExtra(t *testing.T) {
+	c, err := NewClient("token")
+	require.NoError(t, err)
+
+	m := &Message{
+		User:    "user",
+		Message: "message",
+		Title:   "title",
+		Extra: url.Values{
+			"ttl":   {"3600"},
+			"title": {"override"},
+		},
+	}
+	expected := url.Values{
+		"token":   {"token"},
+		"user":    {"user"},
+		"message": {"message"},
+		"title":   {"override"},
+		"ttl":     {"3600"},
+	}
+	assert.Equal(t, expected, c.makeMessageData(m))
+}
+
+func TestTruncateMessage(t *testing.T) {
+	m := &Message{
+		Message:  strings.Repeat("щ", 1025),
+		Title:    strings.Repeat("t", 250),
+		URLTitle: strings.Repeat("u", 101),
+	}
+	actual := truncateMessage(m)
+	assert.Equal(t, strings.Repeat("щ", 1023)+"…", actual.Message)
+	assert.Equal(t, m.Title, actual.Title)
+	assert.Equal(t, strings.Repeat("u", 99)+"…", actual.URLTitle)
+	require.NoError(t, (&Message{User: testUser, Message: actual.Message}).Validate())
+}
+
+func TestSoftFail(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(500)
+	}, WithSoftFail(log.New(&buf, "", 0)))
+
+	err := c.Send(context.Background(), testUser, "message")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), c.SoftFailures())
+	assert.Equal(t, "failed to send message: pushover: 500 Internal Server Error: \n", buf.String())
+}
+
+func TestEmergencyDowngrade(t *testing.T) {
+	var priorities []string
+	var downgraded error
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		priorities = append(priorities, req.FormValue("priority"))
+		if req.FormValue("priority") == "2" {
+			rw.WriteHeader(400)
+			rw.Write([]byte(`{"retry":"invalid","errors":["retry is invalid"],"status":0,"request":"r"}`))
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithEmergencyDowngrade(func(message *Message, err error) {
+		downgraded = err
+	}))
+
+	err := c.SendMessage(context.Background(), &Message{User: testUser, Message: "message", Priority: EmergencyPriority})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "1"}, priorities)
+	require.Error(t, downgraded)
+}
+
+func TestMessageValidateEmergency(t *testing.T) {
+	require.NoError(t, (&Message{User: testUser, Priority: EmergencyPriority}).Validate())
+	require.NoError(t, (&Message{User: testUser, Priority: EmergencyPriority, Retry: 30, Expire: 10800}).Validate())
+	require.NoError(t, (&Message{User: testUser, Priority: HighPriority, Retry: 1}).Validate())
+	require.EqualError(t, (&Message{User: testUser, Priority: EmergencyPriority, Retry: 29}).Validate(),
+		"pushover: emergency retry is too small: 29 seconds (min 30)")
+	require.EqualError(t, (&Message{User: testUser, Priority: EmergencyPriority, Expire: 10801}).Validate(),
+		"pushover: emergency expire is invalid: 10801 seconds (max 10800)")
+
+	c, err := NewClient("token")
+	require.NoError(t, err)
+	data := c.makeMessageData(&Message{Priority: EmergencyPriority})
+	assert.Equal(t, "60", data.Get("retry"))
+	assert.Equal(t, "3600", data.Get("expire"))
+}
+
+func TestConnectionRetry(t *testing.T) {
+	var requests int32
+	h := func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			conn, _, err := rw.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}
+
+	t.Run("Retry", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		c := newTestClient(t, h)
+		require.NoError(t, c.Send(context.Background(), testUser, "message"))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("NoRetry", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		c := newTestClient(t, h, WithConnectionRetry(nil))
+		err := c.Send(context.Background(), testUser, "message")
+		require.Error(t, err)
+		assert.True(t, IsConnectionError(err), "%v", err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestConnectionRetryStalePool(t *testing.T) {
+	// fill the pool with two idle connections, then drop both on the server side
+	var m sync.Mutex
+	var stale bool
+	conns := make(map[string]bool)
+	primed := make(chan struct{})
+	var requests int32
+	h := func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		m.Lock()
+		if stale && conns[req.RemoteAddr] {
+			m.Unlock()
+			conn, _, err := rw.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		conns[req.RemoteAddr] = true
+		if !stale && len(conns) == 2 {
+			close(primed)
+		}
+		m.Unlock()
+
+		if !stale {
+			<-primed
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}
+
+	hc := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: 2}}
+	t.Cleanup(hc.CloseIdleConnections)
+	c := newTestClient(t, h, WithHTTPClient(hc))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.Send(context.Background(), testUser, "message"))
+		}()
+	}
+	wg.Wait()
+
+	m.Lock()
+	stale = true
+	m.Unlock()
+
+	atomic.StoreInt32(&requests, 0)
+	require.NoError(t, c.Send(context.Background(), testUser, "message"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestMessageValidateKeys(t *testing.T) {
+	assert.True(t, IsValidUserKey(testUser))
+	assert.False(t, IsValidUserKey(testUser+"x"))
+	assert.False(t, IsValidUserKey("uQiRzpo4DXghDmr9QzzfQu27cmVRs-"))
+	assert.True(t, IsValidDeviceName("iphone_12-pro"))
+	assert.False(t, IsValidDeviceName("my iphone"))
+	assert.False(t, IsValidDeviceName(strings.Repeat("d", 26)))
+
+	require.NoError(t, (&Message{User: testUser + "," + testUser, Devices: []string{"iphone"}}).Validate())
+	require.EqualError(t, (&Message{User: "user"}).Validate(), `pushover: invalid user key "user"`)
+	require.EqualError(t, (&Message{User: testUser, Devices: []string{"my iphone"}}).Validate(), `pushover: invalid device name "my iphone"`)
+}
+
+func TestMessageValidateLimits(t *testing.T) {
+	users := strings.TrimSuffix(strings.Repeat(testUser+",", MaxUsersPerRequest+1), ",")
+	require.EqualError(t, (&Message{User: users}).Validate(), "pushover: too many user keys: 51 (max 50)")
+
+	err := (&Message{User: testUser, Message: strings.Repeat("щ", MaxMessageLength+1)}).Validate()
+	require.EqualError(t, err, "pushover: message is too long: 1025 characters (max 1024)")
+	assert.True(t, errors.Is(err, ErrMessageTooLong))
+
+	require.EqualError(t, (&Message{User: testUser, URLTitle: strings.Repeat("u", MaxURLTitleLength+1)}).Validate(),
+		"pushover: message URL title is too long: 101 characters (max 100)")
+	require.NoError(t, (&Message{User: testUser, Title: strings.Repeat("t", MaxTitleLength)}).Validate())
+}
+
+func TestMessageValidatePriority(t *testing.T) {
+	require.EqualError(t, (&Message{User: testUser, Priority: 3}).Validate(), "pushover: invalid priority 3")
+}
+
+func TestPerAttemptTimeout(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+	}, WithPerAttemptTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	err := c.Send(context.Background(), testUser, "message")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr), "%v", err)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestClientOptions(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/1/messages.json", req.URL.Path)
+		assert.Equal(t, "github.com/AlekSi/pushover test-agent/1.0", req.Header.Get("User-Agent"))
+		time.Sleep(100 * time.Millisecond)
+	}, WithUserAgent("test-agent/1.0"), WithTimeout(20*time.Millisecond), WithConnectionRetry(nil))
+
+	err := c.Send(context.Background(), testUser, "message")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+}
+
+func TestClockSkewCorrection(t *testing.T) {
+	var timestamps []string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		timestamps = append(timestamps, req.FormValue("timestamp"))
+		rw.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithClockSkewCorrection())
+
+	ts := time.Unix(1600000000, 0)
+	m := &Message{User: testUser, Message: "message", Timestamp: ts}
+	require.NoError(t, c.SendMessage(context.Background(), m))
+	assert.InDelta(t, time.Hour, c.ClockSkew(), float64(2*time.Second))
+	require.NoError(t, c.SendMessage(context.Background(), m))
+
+	require.Len(t, timestamps, 2)
+	assert.Equal(t, "1600000000", timestamps[0])
+	second, err := strconv.ParseInt(timestamps[1], 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, 1600003600, second, 2)
+}
+
+func TestSendMessageWithResponse(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"647d2300-702c-4b38-8b2f-d56326ae460b","receipt":"rLqVuqTRh62UzxtmqiaLzQmVcPgiCy"}`))
+	})
+
+	m := &Message{User: testUser, Message: "message", Priority: EmergencyPriority}
+	res, err := c.SendMessageWithResponse(context.Background(), m)
+	require.NoError(t, err)
+	expected := &Response{
+		Status:  1,
+		Request: "647d2300-702c-4b38-8b2f-d56326ae460b",
+		Receipt: "rLqVuqTRh62UzxtmqiaLzQmVcPgiCy",
+	}
+	res.Start, res.Duration, res.Date = time.Time{}, 0, time.Time{} // checked by TestResponseTiming
+	assert.Equal(t, expected, res)
+}
+
+func TestAPIError(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(400)
+		rw.Write([]byte(`{"user":"invalid","errors":["user identifier is invalid"],` +
+			`"errors_with_keys":{"user":["is invalid"]},"status":0,"request":"5042853c-402d-4a18-abcb-168734a801de"}`))
+	})
+
+	err := c.Send(context.Background(), testUser, "message")
+	var e *APIError
+	require.True(t, errors.As(err, &e), "%v", err)
+	assert.Equal(t, 400, e.HTTPStatus)
+	assert.Equal(t, []string{"user identifier is invalid"}, e.Messages)
+	assert.Equal(t, map[string][]string{"user": {"is invalid"}}, e.Fields)
+	assert.Equal(t, "5042853c-402d-4a18-abcb-168734a801de", e.RequestID)
+	expected := "pushover: 400 Bad Request: user identifier is invalid (request 5042853c-402d-4a18-abcb-168734a801de)"
+	assert.EqualError(t, err, expected)
+	assert.True(t, errors.Is(err, ErrInvalidUser))
+	assert.False(t, errors.Is(err, ErrInvalidToken))
+	assert.False(t, errors.Is(err, ErrMessageTooLong))
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	for msg, expected := range map[string]error{
+		"application token is invalid":                                       ErrInvalidToken,
+		"user identifier is not a valid user, group, or subscribed user key": ErrInvalidUser,
+		"message cannot be longer than 1024 characters":                      ErrMessageTooLong,
+		"user has no active devices":                                         ErrNoActiveDevices,
+	} {
+		err := &APIError{HTTPStatus: 400, Messages: []string{msg}}
+		for _, target := range []error{ErrInvalidToken, ErrInvalidUser, ErrMessageTooLong, ErrNoActiveDevices} {
+			assert.Equal(t, target == expected, errors.Is(err, target), "%q %v", msg, target)
+		}
+	}
+}
+
+func TestMessageClone(t *testing.T) {
+	m := &Message{
+		User:    testUser,
+		Message: "message",
+		Devices: []string{"iphone"},
+		Extra:   url.Values{"ttl": {"60"}},
+	}
+	c := m.Clone()
+	assert.Equal(t, m, c)
+	c.Devices[0] = "ipad"
+	c.Extra["ttl"][0] = "120"
+	assert.Equal(t, "iphone", m.Devices[0])
+	assert.Equal(t, "60", m.Extra.Get("ttl"))
+
+	ms := m.ForDevices("phone", "desktop")
+	require.Len(t, ms, 2)
+	ms[1].Sound = NoneSound
+	assert.Equal(t, []string{"phone"}, ms[0].Devices)
+	assert.Equal(t, Sound(""), ms[0].Sound)
+	assert.Equal(t, []string{"desktop"}, ms[1].Devices)
+	assert.Equal(t, []string{"iphone"}, m.Devices)
+}
+
+func TestRateLimitError(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Limit-App-Limit", "10000")
+		rw.Header().Set("X-Limit-App-Remaining", "0")
+		rw.Header().Set("X-Limit-App-Reset", "1393653600")
+		rw.WriteHeader(429)
+		rw.Write([]byte(`{"errors":["application has exceeded its monthly message limit"],"status":0,"request":"r"}`))
+	})
+
+	err := c.Send(context.Background(), testUser, "message")
+	var e *RateLimitError
+	require.True(t, errors.As(err, &e), "%v", err)
+	assert.Equal(t, 429, e.StatusCode())
+	assert.Contains(t, string(e.Body()), "monthly message limit")
+	expected := Limits{
+		Limit:     10000,
+		Remaining: 0,
+		Reset:     time.Unix(1393653600, 0),
+	}
+	assert.Equal(t, expected, e.Limits)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 429, apiErr.HTTPStatus)
+	assert.True(t, isRateLimited(err))
+}
+
+func TestTemporaryAndFatalErrors(t *testing.T) {
+	ctx := context.Background()
+	var status int
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(status)
+	})
+
+	var temp *TemporaryError
+	var fatal *FatalError
+
+	for _, s := range []int{429, 500, 503} {
+		status = s
+		err := c.Send(ctx, testUser, "message")
+		assert.True(t, errors.As(err, &temp), "%d: %v", s, err)
+		assert.Equal(t, s, temp.StatusCode())
+		assert.True(t, IsRetryable(err))
+		assert.False(t, errors.As(err, &fatal), "%d: %v", s, err)
+	}
+
+	for _, s := range []int{400, 401, 404} {
+		status = s
+		err := c.Send(ctx, testUser, "message")
+		assert.True(t, errors.As(err, &fatal), "%d: %v", s, err)
+		assert.False(t, errors.As(err, &temp), "%d: %v", s, err)
+		assert.Equal(t, s, fatal.StatusCode())
+		assert.False(t, IsRetryable(err))
+	}
+
+	err := c.Send(ctx, "invalid", "message")
+	assert.True(t, errors.As(err, &fatal), "%v", err)
+	assert.Equal(t, 0, fatal.StatusCode())
+	assert.Nil(t, fatal.Body())
+}
+
+func TestDecodeError(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`<html>`))
+	})
+
+	err := c.Send(context.Background(), testUser, "message")
+	var e *Error
+	require.True(t, errors.As(err, &e), "%v", err)
+	assert.Equal(t, "decode", e.Op)
+	assert.False(t, e.Timeout())
+	assert.False(t, e.Temporary())
+	assert.False(t, IsRetryable(err))
+}
+
+func TestHooks(t *testing.T) {
+	var statuses []int
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "value", req.Header.Get("X-Test"))
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithRequestHook(func(req *http.Request) {
+		req.Header.Set("X-Test", "value")
+	}), WithResponseHook(func(resp *http.Response, err error) {
+		require.NoError(t, err)
+		statuses = append(statuses, resp.StatusCode)
+	}))
+
+	require.NoError(t, c.Send(context.Background(), testUser, "message"))
+	assert.Equal(t, []int{200}, statuses)
+}
+
+func TestDebugLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Limit-App-Limit", "10000")
+		rw.Header().Set("X-Limit-App-Remaining", "7496")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithLogger(logger))
+
+	require.NoError(t, c.SendMessage(context.Background(), &Message{User: testUser, Message: "message", Priority: HighPriority}))
+
+	out := buf.String()
+	assert.Contains(t, out, "endpoint=messages.json")
+	assert.Contains(t, out, "token=azGD**************************")
+	assert.Contains(t, out, "user="+testUser)
+	assert.Contains(t, out, "priority=1")
+	assert.Contains(t, out, "status=200")
+	assert.Contains(t, out, "remaining=7496")
+	assert.NotContains(t, out, "azGDORePK8gMaC0QOYAMyEEuzJnyUi")
+}
+
+func TestDebugDump(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithDebugDump(&buf))
+
+	require.NoError(t, c.Send(context.Background(), testUser, "message"))
+
+	out := buf.String()
+	assert.Contains(t, out, "POST /1/messages.json HTTP/1.1")
+	assert.Contains(t, out, "token=azGD**************************")
+	assert.Contains(t, out, "HTTP/1.1 200 OK")
+	assert.Contains(t, out, `{"status":1,"request":"r"}`)
+	assert.NotContains(t, out, "azGDORePK8gMaC0QOYAMyEEuzJnyUi")
+}
+
+func TestSendOptions(t *testing.T) {
+	setFastBackoff(t)
+
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		switch req.FormValue("message") {
+		case "slow":
+			select {
+			case <-req.Context().Done():
+			case <-time.After(300 * time.Millisecond):
+			}
+		case "flaky":
+			if n < 3 {
+				rw.WriteHeader(500)
+				return
+			}
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx := context.Background()
+
+	t.Run("WithRetries", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		require.NoError(t, c.Send(ctx, testUser, "flaky", WithRetries(2)))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("WithAttemptTimeout", func(t *testing.T) {
+		err := c.Send(ctx, testUser, "slow", WithAttemptTimeout(10*time.Millisecond))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("WithoutValidation", func(t *testing.T) {
+		require.Error(t, c.Send(ctx, "user", "message"))
+
+		atomic.StoreInt32(&requests, 0)
+		require.NoError(t, c.Send(ctx, "user", "message", WithoutValidation()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestMessageToken(t *testing.T) {
+	var token string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		token = req.FormValue("token")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx := context.Background()
+
+	require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "message"}))
+	assert.Equal(t, "azGDORePK8gMaC0QOYAMyEEuzJnyUi", token)
+
+	require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "message", Token: "aOtherAppTokenOtherAppTokenXYZ"}))
+	assert.Equal(t, "aOtherAppTokenOtherAppTokenXYZ", token)
+}
+
+func TestEmergencyDeduplication(t *testing.T) {
+	var sent, acknowledged int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/1/messages.json":
+			n := atomic.AddInt32(&sent, 1)
+			assert.Equal(t, "db,disk", req.FormValue("tags"))
+			fmt.Fprintf(rw, `{"status":1,"request":"r","receipt":"receipt%d"}`, n)
+		case "/1/receipts/receipt1.json":
+			assert.Equal(t, "GET", req.Method)
+			assert.Equal(t, "azGDORePK8gMaC0QOYAMyEEuzJnyUi", req.URL.Query().Get("token"))
+			fmt.Fprintf(rw, `{"status":1,"request":"r","acknowledged":%d}`, atomic.LoadInt32(&acknowledged))
+		default:
+			t.Errorf("unexpected request %s", req.URL)
+		}
+	}, WithEmergencyDeduplication())
+
+	ctx := context.Background()
+	m := &Message{User: testUser, Message: "down", Priority: EmergencyPriority, Tags: []string{"db", "disk"}}
+
+	res, err := c.SendMessageWithResponse(ctx, m)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt1", res.Receipt)
+
+	res, err = c.SendMessageWithResponse(ctx, m)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt1", res.Receipt)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sent))
+
+	atomic.StoreInt32(&acknowledged, 1)
+	res, err = c.SendMessageWithResponse(ctx, m)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt2", res.Receipt)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&sent))
+}
+
+func TestResponseTiming(t *testing.T) {
+	date := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Date", date.Format(http.TimeFormat))
+		time.Sleep(10 * time.Millisecond)
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	before := time.Now()
+	res, err := c.SendMessageWithResponse(context.Background(), &Message{User: testUser, Message: "message"})
+	require.NoError(t, err)
+	assert.False(t, res.Start.Before(before))
+	assert.GreaterOrEqual(t, res.Duration, 10*time.Millisecond)
+	assert.True(t, date.Equal(res.Date))
+}
+
+func TestEmergencyDeduplicationRecipients(t *testing.T) {
+	const (
+		otherUser  = "uOtherOtherOtherOtherOtherOthe"
+		otherToken = "aOtherAppTokenOtherAppTokenXYZ"
+	)
+
+	var sent int32
+	receipts := make(map[string]string) // receipt -> token
+	var m sync.Mutex
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == "GET" {
+			receipt := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/1/receipts/"), ".json")
+			m.Lock()
+			token := receipts[receipt]
+			m.Unlock()
+			if token != req.URL.Query().Get("token") {
+				rw.WriteHeader(404)
+				rw.Write([]byte(`{"receipt":"not found","status":0,"request":"r"}`))
+				return
+			}
+			rw.Write([]byte(`{"status":1,"request":"r"}`))
+			return
+		}
+
+		receipt := fmt.Sprintf("receipt%d", atomic.AddInt32(&sent, 1))
+		m.Lock()
+		receipts[receipt] = req.FormValue("token")
+		m.Unlock()
+		fmt.Fprintf(rw, `{"status":1,"request":"r","receipt":"%s"}`, receipt)
+	}, WithEmergencyDeduplication())
+
+	ctx := context.Background()
+	msg := &Message{Message: "down", Priority: EmergencyPriority, Tags: []string{"db"}}
+
+	res, err := c.SendToMany(ctx, []string{testUser, otherUser}, msg)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt1", res[0].Receipt)
+	assert.Equal(t, "receipt2", res[1].Receipt)
+
+	// receipt is checked with the token that sent the message
+	msg = &Message{User: testUser, Token: otherToken, Message: "down", Priority: EmergencyPriority, Tags: []string{"db"}}
+	r, err := c.SendMessageWithResponse(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt3", r.Receipt)
+	r, err = c.SendMessageWithResponse(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt3", r.Receipt)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&sent))
+}
+func TestReceiptTokenNotExposed(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/1/receipts/decode.json" {
+			rw.Write([]byte(`not json`))
+			return
+		}
+		conn, _, err := rw.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}, WithLogger(logger))
+
+	ctx := context.Background()
+	for _, receipt := range []string{"send", "decode"} {
+		_, err := c.Receipt(ctx, receipt)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "/1/receipts/"+receipt+".json")
+		assert.NotContains(t, err.Error(), "azGDORePK8gMaC0QOYAMyEEuzJnyUi")
+	}
+
+	assert.Contains(t, buf.String(), "retrying after connection error")
+	assert.NotContains(t, buf.String(), "azGDORePK8gMaC0QOYAMyEEuzJnyUi")
+}
+
+func TestEmergencyDowngradeValidation(t *testing.T) {
+	var data []url.Values
+	var downgraded error
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		req.ParseForm()
+		data = append(data, req.Form)
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithEmergencyDowngrade(func(message *Message, err error) {
+		downgraded = err
+	}))
+
+	m := &Message{User: testUser, Message: "message", Priority: EmergencyPriority, Retry: 10}
+	require.NoError(t, c.SendMessage(context.Background(), m))
+	require.Len(t, data, 1)
+	assert.Equal(t, "1", data[0].Get("priority"))
+	assert.Empty(t, data[0].Get("retry"))
+	require.EqualError(t, downgraded, "pushover: emergency retry is too small: 10 seconds (min 30)")
+
+	// other validation errors are not fixed by downgrade
+	data, downgraded = nil, nil
+	m = &Message{User: "user", Message: "message", Priority: EmergencyPriority, Retry: 10}
+	require.Error(t, c.SendMessage(context.Background(), m))
+	assert.Empty(t, data)
+	assert.NoError(t, downgraded)
+}
@@ -0,0 +1,48 @@
+package pushover
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxCodeSummaryLength is the maximal length of code message summary, so there is always room for code.
+const maxCodeSummaryLength = MaxMessageLength / 2
+
+// codeCutMark replaces the cut beginning of code.
+const codeCutMark = "…\n"
+
+// NewCodeMessage returns a new monospace message for given user with code, command output, diff, etc.
+// Leading and trailing blank lines are removed.
+// If code is longer than Pushover limit, its beginning is cut, because the end of command output is usually
+// the most interesting part.
+func NewCodeMessage(user, code string) *Message {
+	return NewCodeMessageWithSummary(user, "", code)
+}
+
+// NewCodeMessageWithSummary is like NewCodeMessage, but also prefixes code with a plain text summary line.
+// Summary longer than half of Pushover limit is truncated.
+func NewCodeMessageWithSummary(user, summary, code string) *Message {
+	var prefix string
+	if summary = strings.TrimSpace(summary); summary != "" {
+		prefix = truncate(summary, maxCodeSummaryLength) + "\n\n"
+	}
+
+	code = strings.TrimRight(code, " \t\r\n")
+	code = strings.TrimLeft(code, "\r\n")
+
+	r := []rune(code)
+	if max := MaxMessageLength - utf8.RuneCountInString(prefix); len(r) > max {
+		// keep the tail, starting from the line boundary if possible
+		tail := string(r[len(r)-max+utf8.RuneCountInString(codeCutMark):])
+		if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+			tail = tail[i+1:]
+		}
+		code = codeCutMark + tail
+	}
+
+	return &Message{
+		User:      user,
+		Message:   prefix + code,
+		Monospace: true,
+	}
+}
@@ -0,0 +1,42 @@
+package pushover
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCodeMessage(t *testing.T) {
+	m := NewCodeMessage(testUser, "\n\n  indented\nline\n\n")
+	assert.Equal(t, &Message{User: testUser, Message: "  indented\nline", Monospace: true}, m)
+
+	m = NewCodeMessageWithSummary(testUser, "make failed\n", "error 1\n")
+	assert.Equal(t, "make failed\n\nerror 1", m.Message)
+
+	lines := make([]string, 200)
+	for i := range lines {
+		lines[i] = strings.Repeat("ы", i%20)
+	}
+	m = NewCodeMessageWithSummary(testUser, "summary", strings.Join(lines, "\n"))
+	assert.LessOrEqual(t, utf8.RuneCountInString(m.Message), MaxMessageLength)
+	assert.True(t, strings.HasPrefix(m.Message, "summary\n\n…\n"))
+	assert.True(t, strings.HasSuffix(m.Message, "\n"+lines[len(lines)-1]))
+
+	// the tail starts with a complete line
+	tail := strings.TrimPrefix(m.Message, "summary\n\n…\n")
+	assert.Contains(t, lines, strings.SplitN(tail, "\n", 2)[0])
+}
+
+func TestNewCodeMessageLongSummary(t *testing.T) {
+	summary := strings.Repeat("s", 2000)
+	m := NewCodeMessageWithSummary(testUser, summary, strings.Repeat("code\n", 500))
+	assert.LessOrEqual(t, utf8.RuneCountInString(m.Message), MaxMessageLength)
+	assert.True(t, strings.HasPrefix(m.Message, strings.Repeat("s", maxCodeSummaryLength-1)+"…\n\n…\n"))
+	assert.True(t, strings.HasSuffix(m.Message, "\ncode"))
+
+	// the whole limit is used, without cutting multi-byte characters
+	m = NewCodeMessageWithSummary(testUser, "summary", strings.Repeat("ы", 2000))
+	assert.Equal(t, MaxMessageLength, utf8.RuneCountInString(m.Message))
+}
@@ -0,0 +1,57 @@
+package pushover
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateSuppressed is returned (wrapped in FatalError) for messages suppressed by deduplication,
+// see WithDeduplication.
+var ErrDuplicateSuppressed = errors.New("pushover: duplicate message suppressed")
+
+// defaultDedupKey returns deduplication key consisting of message application token, recipient, priority, and content.
+func defaultDedupKey(m *Message) string {
+	return strings.Join([]string{
+		m.Token,
+		m.User,
+		strings.Join(m.Devices, ","),
+		strconv.Itoa(int(m.Priority)),
+		m.Title,
+		m.Message,
+		m.URL,
+	}, "\x00")
+}
+
+// dedupCheck returns true if message with given key was not seen within deduplication window,
+// and remembers it.
+func (c *Client) dedupCheck(key string, now time.Time) bool {
+	c.dedupM.Lock()
+	defer c.dedupM.Unlock()
+
+	if c.dedupSeen == nil {
+		c.dedupSeen = make(map[string]time.Time)
+	}
+
+	// forget expired keys
+	for k, t := range c.dedupSeen {
+		if now.Sub(t) >= c.dedupWindow {
+			delete(c.dedupSeen, k)
+		}
+	}
+
+	if _, ok := c.dedupSeen[key]; ok {
+		return false
+	}
+	c.dedupSeen[key] = now
+	return true
+}
+
+// dedupForget forgets message with given key, so it can be sent again.
+func (c *Client) dedupForget(key string) {
+	c.dedupM.Lock()
+	defer c.dedupM.Unlock()
+
+	delete(c.dedupSeen, key)
+}
@@ -0,0 +1,82 @@
+package pushover
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplication(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			rw.WriteHeader(500)
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithDeduplication(50*time.Millisecond, nil))
+
+	ctx := context.Background()
+
+	// failed messages are not remembered
+	require.Error(t, c.Send(ctx, testUser, "disk is full"))
+	require.NoError(t, c.Send(ctx, testUser, "disk is full"))
+
+	err := c.Send(ctx, testUser, "disk is full")
+	assert.ErrorIs(t, err, ErrDuplicateSuppressed)
+	var fatal *FatalError
+	assert.ErrorAs(t, err, &fatal)
+	require.NoError(t, c.Send(ctx, testUser, "disk is fine"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, c.Send(ctx, testUser, "disk is full"))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+}
+
+func TestDeduplicationKeyFunc(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithDeduplication(time.Minute, func(m *Message) string { return m.Title }))
+
+	ctx := context.Background()
+
+	require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Title: "disk", Message: "90%"}))
+	assert.ErrorIs(t, c.SendMessage(ctx, &Message{User: testUser, Title: "disk", Message: "95%"}), ErrDuplicateSuppressed)
+}
+
+func TestDeduplicationTokens(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithDeduplication(time.Minute, nil))
+
+	ctx := context.Background()
+
+	// the same text for different applications is not a duplicate
+	require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "disk is full"}))
+	require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "disk is full", Token: "a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5"}))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestDeduplicationSoftFail(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithDeduplication(time.Minute, nil), WithSoftFail(log.New(&buf, "", 0)))
+
+	ctx := context.Background()
+
+	require.NoError(t, c.Send(ctx, testUser, "disk is full"))
+	require.NoError(t, c.Send(ctx, testUser, "disk is full"))
+	assert.Equal(t, uint64(1), c.SoftFailures())
+	assert.Contains(t, buf.String(), "duplicate message suppressed")
+}
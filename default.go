@@ -0,0 +1,62 @@
+package pushover
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+var (
+	defaultM      sync.RWMutex
+	defaultClient *Client // nil until set or initialized from environment
+)
+
+// Default returns the client used by package-level functions.
+//
+// Unless SetDefaultClient was called, the client is created on first use
+// with the application token from PUSHOVER_APP environment variable
+// and the default user (see WithDefaultUser) from PUSHOVER_USER environment variable.
+func Default() *Client {
+	defaultM.RLock()
+	c := defaultClient
+	defaultM.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	defaultM.Lock()
+	defer defaultM.Unlock()
+
+	if defaultClient == nil {
+		defaultClient, _ = NewClient(os.Getenv("PUSHOVER_APP"), WithDefaultUser(os.Getenv("PUSHOVER_USER")))
+	}
+	return defaultClient
+}
+
+// SetDefaultClient replaces the client used by package-level functions.
+// It is safe to call it concurrently with them.
+// If c is nil, the next call to Default creates a new client from environment variables.
+func SetDefaultClient(c *Client) {
+	defaultM.Lock()
+	defer defaultM.Unlock()
+
+	defaultClient = c
+}
+
+// Send is a shortcut for sending a basic message to given user with the default client.
+// If user is empty, the default user is used.
+func Send(ctx context.Context, user, message string, opts ...SendOption) error {
+	return Default().Send(ctx, user, message, opts...)
+}
+
+// SendMessage sends given message with the default client.
+func SendMessage(ctx context.Context, message *Message, opts ...SendOption) error {
+	return Default().SendMessage(ctx, message, opts...)
+}
+
+// SendWithRetries sends given message with the default client, retrying up to the given number of times
+// with exponential backoff if error is retryable (see WithRetries and IsRetryable).
+// It stops when ctx is canceled.
+func SendWithRetries(ctx context.Context, message *Message, retries int) error {
+	return SendMessage(ctx, message, WithRetries(retries))
+}
@@ -0,0 +1,68 @@
+package pushover
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setDefaultClient replaces the default client for the duration of the test.
+func setDefaultClient(t *testing.T, c *Client) {
+	t.Helper()
+
+	defaultM.RLock()
+	old := defaultClient
+	defaultM.RUnlock()
+
+	SetDefaultClient(c)
+	t.Cleanup(func() { SetDefaultClient(old) })
+}
+
+func TestSendWithRetries(t *testing.T) {
+	setFastBackoff(t)
+
+	var requests int
+	setDefaultClient(t, newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		switch req.FormValue("message") {
+		case "fatal":
+			rw.WriteHeader(400)
+		default:
+			if requests < 3 {
+				rw.WriteHeader(500)
+				return
+			}
+			rw.Write([]byte(`{"status":1,"request":"r"}`))
+		}
+	}))
+
+	ctx := context.Background()
+
+	err := SendWithRetries(ctx, &Message{User: testUser, Message: "temporary"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	requests = 0
+	err = SendWithRetries(ctx, &Message{User: testUser, Message: "fatal"}, 2)
+	require.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestDefaultClientFromEnv(t *testing.T) {
+	t.Setenv("PUSHOVER_APP", "azGDORePK8gMaC0QOYAMyEEuzJnyUi")
+	t.Setenv("PUSHOVER_USER", testUser)
+	setDefaultClient(t, nil)
+
+	c := Default()
+	assert.Equal(t, "azGDORePK8gMaC0QOYAMyEEuzJnyUi", c.appToken)
+	assert.Equal(t, testUser, c.defaultUser)
+	assert.Same(t, c, Default())
+
+	other, err := NewClient("token")
+	require.NoError(t, err)
+	SetDefaultClient(other)
+	assert.Same(t, other, Default())
+}
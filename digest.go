@@ -0,0 +1,180 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultDigestInterval is the default interval between Digester flushes.
+const DefaultDigestInterval = time.Minute
+
+// Digester buffers messages per recipient (and application token and formatting)
+// and sends a single combined message for each of them every Interval,
+// or as soon as MaxMessages messages are buffered for the recipient.
+//
+// Combined message has a title with messages count, one line per message (prefixed with its title, if any),
+// and the highest priority of messages, but not higher than HighPriority.
+// Sound, URL and monospace formatting are kept if all messages share them.
+// Lines that do not fit into MaxMessageLength are replaced with a count of omitted messages.
+// A single buffered message is sent as is.
+//
+// Digester is safe for concurrent use.
+type Digester struct {
+	Sender      Sender
+	Interval    time.Duration // defaults to DefaultDigestInterval
+	MaxMessages int           // if positive, recipient's messages are sent when that many are buffered
+
+	// OnError, if set, is called for every error returned by Sender during periodic flushes.
+	OnError func(err error)
+
+	m       sync.Mutex
+	buffers map[string][]*Message // digest key -> buffered messages
+}
+
+// Add buffers message. If MaxMessages messages are buffered for its recipient, they are sent.
+func (d *Digester) Add(ctx context.Context, message *Message) error {
+	key := strings.Join([]string{message.Token, message.User, strings.Join(message.Devices, ","), strconv.FormatBool(message.HTML)}, "\x00")
+
+	d.m.Lock()
+	if d.buffers == nil {
+		d.buffers = make(map[string][]*Message)
+	}
+	d.buffers[key] = append(d.buffers[key], message)
+	var messages []*Message
+	if d.MaxMessages > 0 && len(d.buffers[key]) >= d.MaxMessages {
+		messages = d.buffers[key]
+		delete(d.buffers, key)
+	}
+	d.m.Unlock()
+
+	if messages == nil {
+		return nil
+	}
+	return d.Sender.SendMessage(ctx, digest(messages))
+}
+
+// Flush sends all buffered messages and returns all errors joined with errors.Join.
+func (d *Digester) Flush(ctx context.Context) error {
+	d.m.Lock()
+	buffers := d.buffers
+	d.buffers = nil
+	d.m.Unlock()
+
+	var errs []error
+	for _, messages := range buffers {
+		if err := d.Sender.SendMessage(ctx, digest(messages)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run flushes buffered messages every interval until ctx is canceled.
+// Messages buffered at that time are not sent; call Flush with another context to send them.
+func (d *Digester) Run(ctx context.Context) error {
+	interval := d.Interval
+	if interval <= 0 {
+		interval = DefaultDigestInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		if err := d.Flush(ctx); err != nil && d.OnError != nil && ctx.Err() == nil {
+			d.OnError(err)
+		}
+	}
+}
+
+// digest returns combined message for given messages with the same recipient, token and HTML formatting.
+func digest(messages []*Message) *Message {
+	if len(messages) == 1 {
+		return messages[0]
+	}
+
+	first := messages[0]
+	res := &Message{
+		User:      first.User,
+		Token:     first.Token,
+		Devices:   first.Devices,
+		Title:     fmt.Sprintf("%d messages", len(messages)),
+		Priority:  first.Priority,
+		HTML:      first.HTML,
+		Monospace: first.Monospace,
+		Sound:     first.Sound,
+		URL:       first.URL,
+		URLTitle:  first.URLTitle,
+	}
+	for _, m := range messages {
+		if m.Priority > res.Priority {
+			res.Priority = m.Priority
+		}
+		if !m.Monospace {
+			res.Monospace = false
+		}
+		if m.Sound != res.Sound {
+			res.Sound = ""
+		}
+		if m.URL != res.URL || m.URLTitle != res.URLTitle {
+			res.URL, res.URLTitle = "", ""
+		}
+	}
+	if res.Priority > HighPriority {
+		res.Priority = HighPriority
+	}
+
+	var text strings.Builder
+	var length int
+	for i, m := range messages {
+		line := m.Message
+		if m.Title != "" {
+			title := m.Title
+			if m.HTML {
+				title = html.EscapeString(title)
+			}
+			line = title + ": " + line
+		}
+		if i > 0 {
+			line = "\n" + line
+		}
+
+		// leave space for the count of omitted messages
+		var reserve int
+		if rest := len(messages) - i - 1; rest > 0 {
+			reserve = utf8.RuneCountInString(omitted(rest))
+		}
+		l := utf8.RuneCountInString(line)
+		if length+l+reserve > MaxMessageLength {
+			if length > 0 {
+				text.WriteString(omitted(len(messages) - i))
+				break
+			}
+			line = truncate(line, MaxMessageLength-reserve)
+			l = utf8.RuneCountInString(line)
+		}
+		text.WriteString(line)
+		length += l
+	}
+
+	res.Message = text.String()
+	return res
+}
+
+// omitted returns a line with the count of omitted messages.
+func omitted(n int) string {
+	return fmt.Sprintf("\n…and %d more", n)
+}
@@ -0,0 +1,112 @@
+package pushover
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigester(t *testing.T) {
+	var m sync.Mutex
+	var sent []*Message
+	d := &Digester{
+		Sender: senderFunc(func(ctx context.Context, message *Message) error {
+			m.Lock()
+			sent = append(sent, message)
+			m.Unlock()
+			return nil
+		}),
+		MaxMessages: 3,
+	}
+
+	ctx := context.Background()
+	for _, text := range []string{"build started", "tests passed", "build finished"} {
+		require.NoError(t, d.Add(ctx, &Message{User: "ci", Message: text}))
+	}
+	require.NoError(t, d.Add(ctx, &Message{User: "ops", Title: "disk", Message: "90%", Priority: LowPriority}))
+
+	require.Len(t, sent, 1)
+	expected := &Message{
+		User:    "ci",
+		Title:   "3 messages",
+		Message: "build started\ntests passed\nbuild finished",
+	}
+	assert.Equal(t, expected, sent[0])
+
+	require.NoError(t, d.Flush(ctx))
+	require.Len(t, sent, 2)
+	assert.Equal(t, &Message{User: "ops", Title: "disk", Message: "90%", Priority: LowPriority}, sent[1])
+
+	t.Run("Run", func(t *testing.T) {
+		d.Interval = 10 * time.Millisecond
+		require.NoError(t, d.Add(ctx, &Message{User: "ops", Message: "hello"}))
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, d.Run(ctx))
+
+		m.Lock()
+		defer m.Unlock()
+		require.Len(t, sent, 3)
+		assert.Equal(t, "hello", sent[2].Message)
+	})
+}
+
+func TestDigesterTokensAndFormatting(t *testing.T) {
+	const otherToken = "a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5"
+	var sent []*Message
+	d := &Digester{
+		Sender: senderFunc(func(ctx context.Context, message *Message) error {
+			sent = append(sent, message)
+			return nil
+		}),
+	}
+
+	ctx := context.Background()
+	for _, m := range []*Message{
+		{User: "ops", Message: "one", Sound: SirenSound},
+		{User: "ops", Message: "two", Sound: SirenSound},
+		{User: "ops", Token: otherToken, Message: "three", Sound: SirenSound},
+		{User: "ops", Token: otherToken, Message: "four", Sound: PushoverSound},
+		{User: "ops", Token: otherToken, Title: "a<b", Message: "<b>five</b>", HTML: true},
+		{User: "ops", Token: otherToken, Message: "<i>six</i>", HTML: true},
+	} {
+		require.NoError(t, d.Add(ctx, m))
+	}
+	require.NoError(t, d.Flush(ctx))
+
+	sort.Slice(sent, func(i, j int) bool { return sent[i].Message < sent[j].Message })
+	expected := []*Message{
+		{User: "ops", Token: otherToken, Title: "2 messages", Message: "a&lt;b: <b>five</b>\n<i>six</i>", HTML: true},
+		{User: "ops", Title: "2 messages", Message: "one\ntwo", Sound: SirenSound},
+		{User: "ops", Token: otherToken, Title: "2 messages", Message: "three\nfour"},
+	}
+	assert.Equal(t, expected, sent)
+}
+
+func TestDigest(t *testing.T) {
+	messages := make([]*Message, 20)
+	for i := range messages {
+		messages[i] = &Message{User: "ops", Message: strings.Repeat("x", 100)}
+	}
+	messages[5].Priority = EmergencyPriority
+	messages[19].Priority = LowestPriority
+
+	actual := digest(messages)
+	assert.Equal(t, HighPriority, actual.Priority)
+	assert.Equal(t, "20 messages", actual.Title)
+	assert.LessOrEqual(t, utf8.RuneCountInString(actual.Message), MaxMessageLength)
+	assert.True(t, strings.HasSuffix(actual.Message, "\n…and 10 more"), "%s", actual.Message)
+
+	long := []*Message{{Message: strings.Repeat("x", 2000)}, {Message: "short"}}
+	actual = digest(long)
+	assert.LessOrEqual(t, utf8.RuneCountInString(actual.Message), MaxMessageLength)
+	assert.True(t, strings.HasSuffix(actual.Message, "…\nshort"), "%s", actual.Message)
+}
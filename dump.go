@@ -0,0 +1,37 @@
+package pushover
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httputil"
+)
+
+// dumpRequest writes request dump to debug dump writer, with token masked.
+// Request body is preserved.
+func (c *Client) dumpRequest(req *http.Request, token string) {
+	b, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		return
+	}
+	c.writeDump(b, token)
+}
+
+// dumpResponse writes response dump with given body to debug dump writer, with token masked.
+func (c *Client) dumpResponse(resp *http.Response, body []byte, token string) {
+	b, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return
+	}
+	c.writeDump(append(b, body...), token)
+}
+
+// writeDump writes b with masked token, followed by an empty line.
+func (c *Client) writeDump(b []byte, token string) {
+	if token != "" {
+		b = bytes.ReplaceAll(b, []byte(token), []byte(redactToken(token)))
+	}
+
+	c.dumpM.Lock()
+	defer c.dumpM.Unlock()
+	c.debugDump.Write(append(b, "\n\n"...))
+}
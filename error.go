@@ -0,0 +1,289 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Errors returned by Pushover API for common problems.
+// They can be checked with errors.Is; APIError matches them by inspecting its error messages.
+var (
+	ErrInvalidToken    = errors.New("pushover: invalid application token")
+	ErrInvalidUser     = errors.New("pushover: invalid user key")
+	ErrMessageTooLong  = errors.New("pushover: message is too long")
+	ErrNoActiveDevices = errors.New("pushover: user has no active devices")
+)
+
+// APIError represents an unsuccessful Pushover API response.
+type APIError struct {
+	HTTPStatus int                 // HTTP response status code
+	Messages   []string            // error messages from "errors" response field
+	Fields     map[string][]string // error messages by parameter name from "errors_with_keys" response field, if present
+	RequestID  string              // request ID from "request" response field
+
+	body []byte
+}
+
+// Error implements error interface.
+// Error message is formatted on each call, so errors that are only checked, not printed, are cheap.
+func (e *APIError) Error() string {
+	status := fmt.Sprintf("%d %s", e.HTTPStatus, http.StatusText(e.HTTPStatus))
+	if len(e.Messages) == 0 {
+		return fmt.Sprintf("pushover: %s: %s", status, e.body)
+	}
+
+	res := fmt.Sprintf("pushover: %s: %s", status, strings.Join(e.Messages, "; "))
+	if e.RequestID != "" {
+		res += " (request " + e.RequestID + ")"
+	}
+	return res
+}
+
+// StatusCode returns HTTP response status code.
+func (e *APIError) StatusCode() int {
+	return e.HTTPStatus
+}
+
+// Body returns raw HTTP response body, truncated to 4096 bytes.
+func (e *APIError) Body() []byte {
+	return e.body
+}
+
+// Retryable returns true for server errors and rate limit errors.
+func (e *APIError) Retryable() bool {
+	return e.HTTPStatus >= 500 || e.HTTPStatus == http.StatusTooManyRequests
+}
+
+// Is returns true if target is one of the package's sentinel errors matching API error messages.
+func (e *APIError) Is(target error) bool {
+	for _, m := range e.Messages {
+		m = strings.ToLower(m)
+		var match bool
+		switch target {
+		case ErrInvalidToken:
+			match = strings.Contains(m, "token") && strings.Contains(m, "invalid")
+		case ErrInvalidUser:
+			match = strings.Contains(m, "user") && (strings.Contains(m, "invalid") || strings.Contains(m, "not a valid"))
+		case ErrMessageTooLong:
+			match = strings.Contains(m, "message") && (strings.Contains(m, "too long") || strings.Contains(m, "longer than"))
+		case ErrNoActiveDevices:
+			match = strings.Contains(m, "no active devices")
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitError is returned when application's monthly message limit is exceeded.
+type RateLimitError struct {
+	Limits           // limits from response headers
+	Err    *APIError // underlying API error
+}
+
+// Error implements error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s (limit %d, remaining %d, reset at %s)", e.Err, e.Limit, e.Remaining, e.Reset.Format(time.RFC3339))
+}
+
+// Unwrap returns underlying API error.
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns HTTP response status code.
+func (e *RateLimitError) StatusCode() int {
+	return e.Err.StatusCode()
+}
+
+// Body returns raw HTTP response body.
+func (e *RateLimitError) Body() []byte {
+	return e.Err.Body()
+}
+
+// Retryable returns true: request may succeed after limits reset.
+func (e *RateLimitError) Retryable() bool {
+	return true
+}
+
+// Retryable is implemented by all error types returned by the client.
+type Retryable interface {
+	error
+	Retryable() bool
+}
+
+// IsRetryable returns true if err (or any error it wraps) implements Retryable interface
+// and reports that request may succeed if retried.
+func IsRetryable(err error) bool {
+	var r Retryable
+	return errors.As(err, &r) && r.Retryable()
+}
+
+// Error represents a failure to send request or to read or decode response.
+// It implements net.Error interface.
+type Error struct {
+	Op  string // "send", "read" or "decode"
+	URL string // request URL
+	Err error  // underlying error
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("pushover: %s %s: %s", e.Op, e.URL, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Timeout returns true if error is caused by timeout.
+func (e *Error) Timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var t interface{ Timeout() bool }
+	return errors.As(e.Err, &t) && t.Timeout()
+}
+
+// Temporary returns true if error is caused by timeout or connection-level failure.
+func (e *Error) Temporary() bool {
+	return e.Timeout() || IsConnectionError(e.Err)
+}
+
+// Retryable returns true for network errors, and false for response decoding errors.
+func (e *Error) Retryable() bool {
+	return e.Op != "decode"
+}
+
+// TemporaryError wraps errors that may go away if request is retried later:
+// network errors, server errors, and rate limit errors.
+type TemporaryError struct {
+	Err error
+}
+
+// Error implements error interface.
+func (e *TemporaryError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *TemporaryError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns HTTP response status code, or 0 if there was no response.
+func (e *TemporaryError) StatusCode() int {
+	return statusCode(e.Err)
+}
+
+// Body returns raw HTTP response body, or nil if there was no response.
+func (e *TemporaryError) Body() []byte {
+	return body(e.Err)
+}
+
+// Retryable returns true.
+func (e *TemporaryError) Retryable() bool {
+	return true
+}
+
+// FatalError wraps errors that will not go away if request is retried as is:
+// validation and authentication errors.
+type FatalError struct {
+	Err error
+}
+
+// Error implements error interface.
+func (e *FatalError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *FatalError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns HTTP response status code, or 0 if there was no response.
+func (e *FatalError) StatusCode() int {
+	return statusCode(e.Err)
+}
+
+// Body returns raw HTTP response body, or nil if there was no response.
+func (e *FatalError) Body() []byte {
+	return body(e.Err)
+}
+
+// Retryable returns false.
+func (e *FatalError) Retryable() bool {
+	return false
+}
+
+// statusCode returns HTTP response status code from APIError in err chain, or 0.
+func statusCode(err error) int {
+	var e *APIError
+	if errors.As(err, &e) {
+		return e.StatusCode()
+	}
+	return 0
+}
+
+// body returns raw HTTP response body from APIError in err chain, or nil.
+func body(err error) []byte {
+	var e *APIError
+	if errors.As(err, &e) {
+		return e.Body()
+	}
+	return nil
+}
+
+// wrapError wraps non-nil request error in TemporaryError or FatalError, depending on its Retryable method.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if IsRetryable(err) {
+		return &TemporaryError{Err: err}
+	}
+	return &FatalError{Err: err}
+}
+
+// isRateLimited returns true if err is an API error caused by exceeded rate limit.
+func isRateLimited(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.HTTPStatus == http.StatusTooManyRequests
+}
+
+// isFatal returns true if err is an API error that will not go away if request is repeated as is.
+func isFatal(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.HTTPStatus >= 400 && e.HTTPStatus < 500 && e.HTTPStatus != http.StatusTooManyRequests
+}
+
+// IsConnectionError returns true if err is a connection-level failure that happened before a response was received:
+// connection reset, broken pipe, or connection closed by the server.
+func IsConnectionError(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// check interfaces
+var (
+	_ net.Error = (*Error)(nil)
+	_ Retryable = (*Error)(nil)
+	_ Retryable = (*APIError)(nil)
+	_ Retryable = (*RateLimitError)(nil)
+	_ Retryable = (*TemporaryError)(nil)
+	_ Retryable = (*FatalError)(nil)
+)
@@ -0,0 +1,37 @@
+package pushover
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResponseLargeBody(t *testing.T) {
+	b := bytes.Repeat([]byte("x"), maxErrorBodySize*2)
+	_, err := parseResponse("url", 502, nil, b)
+	var e *APIError
+	require.ErrorAs(t, err, &e)
+	assert.Len(t, e.Body(), maxErrorBodySize)
+	assert.Equal(t, maxErrorBodySize, cap(e.Body()))
+}
+
+func BenchmarkParseResponseError(b *testing.B) {
+	for name, body := range map[string][]byte{
+		"API":  []byte(`{"user":"invalid","errors":["user identifier is invalid"],"status":0,"request":"5042853c-402d-4a18-abcb-168734a801de"}`),
+		"HTML": bytes.Repeat([]byte("<html>"), 10000),
+	} {
+		b.Run(name, func(b *testing.B) {
+			h := make(http.Header)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := parseResponse("url", 400, h, body)
+				if err == nil {
+					b.Fatal("expected error")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package pushover
+
+import (
+	"html"
+	"strings"
+)
+
+// HTMLBuilder builds message text with HTML formatting supported by Pushover.
+// All strings passed to its methods are escaped, so it is safe to use with arbitrary user input.
+//
+// See https://pushover.net/api#html.
+type HTMLBuilder struct {
+	b strings.Builder
+}
+
+// Text adds plain text.
+func (h *HTMLBuilder) Text(s string) *HTMLBuilder {
+	h.b.WriteString(html.EscapeString(s))
+	return h
+}
+
+// Bold adds bold text.
+func (h *HTMLBuilder) Bold(s string) *HTMLBuilder {
+	return h.wrap("<b>", s, "</b>")
+}
+
+// Italic adds italic text.
+func (h *HTMLBuilder) Italic(s string) *HTMLBuilder {
+	return h.wrap("<i>", s, "</i>")
+}
+
+// Underline adds underlined text.
+func (h *HTMLBuilder) Underline(s string) *HTMLBuilder {
+	return h.wrap("<u>", s, "</u>")
+}
+
+// Color adds text with given color (for example, "#ff0000" or "red").
+func (h *HTMLBuilder) Color(color, s string) *HTMLBuilder {
+	return h.wrap(`<font color="`+html.EscapeString(color)+`">`, s, "</font>")
+}
+
+// Link adds a link with given URL and text.
+func (h *HTMLBuilder) Link(url, s string) *HTMLBuilder {
+	return h.wrap(`<a href="`+html.EscapeString(url)+`">`, s, "</a>")
+}
+
+func (h *HTMLBuilder) wrap(open, s, close string) *HTMLBuilder {
+	h.b.WriteString(open)
+	h.b.WriteString(html.EscapeString(s))
+	h.b.WriteString(close)
+	return h
+}
+
+// String returns built HTML text.
+func (h *HTMLBuilder) String() string {
+	return h.b.String()
+}
+
+// Message returns a new HTML message with built text for given user.
+func (h *HTMLBuilder) Message(user string) *Message {
+	return &Message{
+		User:    user,
+		Message: h.String(),
+		HTML:    true,
+	}
+}
@@ -0,0 +1,23 @@
+package pushover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLBuilder(t *testing.T) {
+	var h HTMLBuilder
+	h.Text("Build ").Bold("<script>").Text(" failed: ").
+		Italic("a & b").Text(", ").
+		Underline("u").Text(", ").
+		Color(`#ff0000"`, "red").Text(", ").
+		Link("https://example.com/?a=1&b=2", "details")
+
+	expected := `Build <b>&lt;script&gt;</b> failed: <i>a &amp; b</i>, <u>u</u>, ` +
+		`<font color="#ff0000&#34;">red</font>, <a href="https://example.com/?a=1&amp;b=2">details</a>`
+	assert.Equal(t, expected, h.String())
+
+	m := h.Message(testUser)
+	assert.Equal(t, &Message{User: testUser, Message: expected, HTML: true}, m)
+}
@@ -0,0 +1,100 @@
+package pushover
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type labelsKey struct{}
+
+// ContextWithLabels returns a copy of ctx with given labels (for example, request ID or tenant).
+// Labels are merged with labels already present in ctx; new values override old ones.
+//
+// Client appends labels from context to the text of messages sent with it,
+// shortening the text if needed to keep labels within MaxMessageLength,
+// and adds them to request log records.
+func ContextWithLabels(ctx context.Context, labels map[string]string) context.Context {
+	old := LabelsFromContext(ctx)
+	merged := make(map[string]string, len(old)+len(labels))
+	for k, v := range old {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, labelsKey{}, merged)
+}
+
+// LabelsFromContext returns labels from ctx, or nil.
+// Returned map should not be modified.
+func LabelsFromContext(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(labelsKey{}).(map[string]string)
+	return labels
+}
+
+// formatLabels returns labels as "key1=value1 key2=value2" string sorted by key.
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
+	}
+	return strings.Join(parts, " ")
+}
+
+// labelsSuffix returns labels from ctx formatted for appending to message text, or empty string.
+// Labels are escaped for HTML messages.
+func labelsSuffix(ctx context.Context, isHTML bool) string {
+	labels := LabelsFromContext(ctx)
+	if len(labels) == 0 {
+		return ""
+	}
+
+	s := formatLabels(labels)
+	if isHTML {
+		s = html.EscapeString(s)
+	}
+	return "\n\n" + s
+}
+
+// appendLabels returns a copy of message with labels suffix appended to the text.
+// Text that fits into MaxMessageLength is shortened to leave room for the suffix,
+// so labels are never cut from messages sized by callers or by truncation.
+func appendLabels(message *Message, suffix string) *Message {
+	m := *message
+	room := MaxMessageLength - utf8.RuneCountInString(suffix)
+	if room > 0 && utf8.RuneCountInString(m.Message) <= MaxMessageLength {
+		m.Message = truncate(m.Message, room)
+	}
+	m.Message += suffix
+	return &m
+}
+
+// labelsAttrs returns labels from ctx as log attributes sorted by key.
+func labelsAttrs(ctx context.Context) []slog.Attr {
+	labels := LabelsFromContext(ctx)
+	if len(labels) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.String(k, labels[k])
+	}
+	return attrs
+}
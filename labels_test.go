@@ -0,0 +1,86 @@
+package pushover
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWithLabels(t *testing.T) {
+	var text string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		text = req.FormValue("message")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx := ContextWithLabels(context.Background(), map[string]string{"tenant": "acme", "request_id": "1"})
+	ctx = ContextWithLabels(ctx, map[string]string{"request_id": "2"})
+	assert.Equal(t, map[string]string{"tenant": "acme", "request_id": "2"}, LabelsFromContext(ctx))
+
+	m := &Message{User: testUser, Message: "message"}
+	require.NoError(t, c.SendMessage(ctx, m))
+	assert.Equal(t, "message\n\nrequest_id=2 tenant=acme", text)
+	assert.Equal(t, "message", m.Message)
+
+	require.NoError(t, c.SendMessage(context.Background(), m))
+	assert.Equal(t, "message", text)
+}
+
+func TestLabelsRoom(t *testing.T) {
+	var m sync.Mutex
+	var texts []string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		m.Lock()
+		texts = append(texts, req.FormValue("message"))
+		m.Unlock()
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithTruncation())
+
+	ctx := ContextWithLabels(context.Background(), map[string]string{"tenant": "acme"})
+
+	t.Run("Truncation", func(t *testing.T) {
+		texts = nil
+		require.NoError(t, c.Send(ctx, testUser, strings.Repeat("x", MaxMessageLength+10)))
+		require.Len(t, texts, 1)
+		assert.Equal(t, MaxMessageLength, utf8.RuneCountInString(texts[0]))
+		assert.True(t, strings.HasSuffix(texts[0], "…\n\ntenant=acme"), "%q", texts[0])
+	})
+
+	t.Run("SendLongMessage", func(t *testing.T) {
+		texts = nil
+		text := strings.Repeat(strings.Repeat("x", 50)+"\n", 40)
+		require.NoError(t, c.SendLongMessage(ctx, &Message{User: testUser, Message: text}))
+		require.Len(t, texts, 3)
+		for _, text := range texts {
+			assert.LessOrEqual(t, utf8.RuneCountInString(text), MaxMessageLength)
+			assert.True(t, strings.HasSuffix(text, "x\n\ntenant=acme"), "%q", text)
+		}
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		texts = nil
+		ctx := ContextWithLabels(ctx, map[string]string{"user": "<b>&"})
+		require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "message", HTML: true}))
+		assert.Equal(t, []string{"message\n\ntenant=acme user=&lt;b&gt;&amp;"}, texts)
+	})
+}
+
+func TestLabelsLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithLogger(logger))
+
+	ctx := ContextWithLabels(context.Background(), map[string]string{"tenant": "acme", "request_id": "1"})
+	require.NoError(t, c.Send(ctx, testUser, "message"))
+	assert.Contains(t, buf.String(), "labels.request_id=1 labels.tenant=acme")
+}
@@ -0,0 +1,75 @@
+package pushover
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	mdLinkRE       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRE       = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalicStarRE = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	mdItalicUndRE  = regexp.MustCompile(`(^|[^\pL\pN_])_([^_\s][^_]*)_($|[^\pL\pN_])`)
+)
+
+// MarkdownMessage returns a new HTML message with text converted from a subset of Markdown:
+// bold (**text** and __text__), italic (*text* and _text_), links ([text](url)), and inline code (`code`).
+// Pushover does not support inline code formatting, so code is sent as is, without Markdown conversion.
+// All other HTML in md is escaped.
+//
+// It returns an error if converted text is longer than Pushover limit.
+func MarkdownMessage(md string) (*Message, error) {
+	text := markdownToHTML(md)
+	if l := utf8.RuneCountInString(text); l > MaxMessageLength {
+		return nil, fmt.Errorf("pushover: converted message is too long: %d characters (max %d)", l, MaxMessageLength)
+	}
+
+	return &Message{
+		Message: text,
+		HTML:    true,
+	}, nil
+}
+
+// markdownToHTML converts supported Markdown subset to Pushover HTML.
+func markdownToHTML(md string) string {
+	var res strings.Builder
+
+	// odd parts are inline code
+	parts := strings.Split(md, "`")
+	if len(parts)%2 == 0 {
+		// unpaired backtick: treat it as text
+		last := len(parts) - 1
+		parts[last-1] += "`" + parts[last]
+		parts = parts[:last]
+	}
+	for i, part := range parts {
+		if i%2 == 1 {
+			res.WriteString(html.EscapeString(part))
+			continue
+		}
+
+		prev := 0
+		for _, m := range mdLinkRE.FindAllStringSubmatchIndex(part, -1) {
+			res.WriteString(markdownEmphasis(part[prev:m[0]]))
+			res.WriteString(`<a href="` + html.EscapeString(part[m[4]:m[5]]) + `">`)
+			res.WriteString(markdownEmphasis(part[m[2]:m[3]]))
+			res.WriteString(`</a>`)
+			prev = m[1]
+		}
+		res.WriteString(markdownEmphasis(part[prev:]))
+	}
+
+	return res.String()
+}
+
+// markdownEmphasis escapes text and converts bold and italic Markdown.
+func markdownEmphasis(s string) string {
+	s = html.EscapeString(s)
+	s = mdBoldRE.ReplaceAllString(s, "<b>$1$2</b>")
+	s = mdItalicStarRE.ReplaceAllString(s, "<i>$1</i>")
+	s = mdItalicUndRE.ReplaceAllString(s, "$1<i>$2</i>$3")
+	return s
+}
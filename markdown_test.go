@@ -0,0 +1,30 @@
+package pushover
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownMessage(t *testing.T) {
+	for md, expected := range map[string]string{
+		"plain <text> & more":               "plain &lt;text&gt; &amp; more",
+		"**bold** and __bold__":             "<b>bold</b> and <b>bold</b>",
+		"*italic* and _italic_":             "<i>italic</i> and <i>italic</i>",
+		"snake_case_name stays":             "snake_case_name stays",
+		"2 * 3 * 4":                         "2 * 3 * 4",
+		"see [**docs**](https://x.y/a_b_c)": `see <a href="https://x.y/a_b_c"><b>docs</b></a>`,
+		"run `rm -rf *tmp*` now":            "run rm -rf *tmp* now",
+		"unpaired ` backtick":               "unpaired ` backtick",
+	} {
+		m, err := MarkdownMessage(md)
+		require.NoError(t, err)
+		assert.Equal(t, expected, m.Message, "%q", md)
+		assert.True(t, m.HTML)
+	}
+
+	_, err := MarkdownMessage(strings.Repeat("<", 300))
+	require.EqualError(t, err, "pushover: converted message is too long: 1200 characters (max 1024)")
+}
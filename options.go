@@ -0,0 +1,292 @@
+package pushover
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures Client.
+type Option func(*Client)
+
+// WithDefaultUser sets user/group key used for messages with empty Message.User.
+func WithDefaultUser(user string) Option {
+	return func(c *Client) {
+		c.defaultUser = user
+	}
+}
+
+// WithRecipientResolver sets resolver for recipient aliases.
+// If Message.User is not a list of valid user keys, it is treated as an alias and resolved;
+// resolved devices are used if Message.Devices is empty.
+func WithRecipientResolver(r RecipientResolver) Option {
+	return func(c *Client) {
+		c.resolver = r
+	}
+}
+
+// WithHTTPClient sets HTTP client used for requests. By default, http.DefaultClient is used.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithBaseURL sets Pushover API base URL. By default, DefaultBaseURL is used.
+func WithBaseURL(baseURL string) Option {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithUserAgent appends given string to User-Agent header value.
+// Resulting value is DefaultUserAgent, space, and userAgent, so Pushover still can identify this package,
+// while services can identify themselves.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = DefaultUserAgent + " " + userAgent
+	}
+}
+
+// WithTimeout sets timeout for each API call, including connection retries.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithLogger sets logger for client events such as retries. By default, nothing is logged.
+// Each request is logged at debug level with endpoint, recipient, priority, duration,
+// HTTP status, and remaining monthly quota; the application token is redacted.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithPrioritySounds sets sounds used for messages with given priorities when Message.Sound is empty.
+func WithPrioritySounds(sounds map[Priority]Sound) Option {
+	m := make(map[Priority]Sound, len(sounds))
+	for p, s := range sounds {
+		m[p] = s
+	}
+
+	return func(c *Client) {
+		c.prioritySounds = m
+	}
+}
+
+// WithJSON makes client send request bodies as JSON instead of form encoding.
+func WithJSON() Option {
+	return func(c *Client) {
+		c.jsonBody = true
+	}
+}
+
+// WithTruncation makes client truncate message text, title, URL and URL title to Pushover limits
+// (with an ellipsis at the end) instead of sending them as is and getting an error from the API.
+func WithTruncation() Option {
+	return func(c *Client) {
+		c.truncate = true
+	}
+}
+
+// WithSoftFail makes SendMessage, Send and SendGlance never return errors.
+// Failures are logged to given logger (or to the standard logger if nil) and counted instead;
+// see Client.SoftFailures.
+func WithSoftFail(l *log.Logger) Option {
+	if l == nil {
+		l = log.Default()
+	}
+
+	return func(c *Client) {
+		c.softFail = l
+	}
+}
+
+// WithEmergencyDowngrade makes client send emergency priority messages with high priority
+// if they fail validation because of invalid retry or expire parameters (see Message.Validate),
+// or if the API rejects them (for example, because of invalid callback URL),
+// so that recipients are still notified. Emergency parameters are not sent in that case.
+// If resending succeeds, f (if not nil) is called with the original message and error, and no error is returned.
+func WithEmergencyDowngrade(f func(message *Message, err error)) Option {
+	if f == nil {
+		f = func(*Message, error) {}
+	}
+
+	return func(c *Client) {
+		c.emergencyDowngrade = f
+	}
+}
+
+// WithEmergencyDeduplication enables suppression of duplicate emergency priority messages.
+// If an emergency message with any of the same Message.Tags was sent by this client
+// and is neither acknowledged nor expired yet (see Client.Receipt),
+// a new message is not sent; the receipt of the outstanding one is returned instead.
+// That prevents double-paging during flapping incidents.
+func WithEmergencyDeduplication() Option {
+	return func(c *Client) {
+		c.emergencyDedup = true
+	}
+}
+
+// WithDeduplication suppresses messages with the same key sent within given window after the first one;
+// FatalError wrapping ErrDuplicateSuppressed is returned for them (unless WithSoftFail is used).
+// Messages that failed to be sent are not remembered.
+// If keyFunc is nil, the key consists of message application token, recipient, priority, title, text, and URL.
+func WithDeduplication(window time.Duration, keyFunc func(*Message) string) Option {
+	if keyFunc == nil {
+		keyFunc = defaultDedupKey
+	}
+	return func(c *Client) {
+		c.dedupWindow = window
+		c.dedupKey = keyFunc
+	}
+}
+
+// WithQuotaWarning sets a function that is called when the number of remaining messages
+// in application's monthly limit drops below threshold (see Client.Limits).
+// It is called once for each application token, until remaining count is restored by the monthly reset.
+// f may, for example, send a message about that with the same client.
+func WithQuotaWarning(threshold int, f func(l Limits)) Option {
+	return func(c *Client) {
+		c.quotaThreshold = threshold
+		c.quotaWarning = f
+	}
+}
+
+// WithCircuitBreaker makes requests fail fast with ErrCircuitOpen after threshold consecutive temporary failures,
+// so callers do not pile up waiting for timeouts while Pushover API is down.
+// After cooldown, a single probe request is sent; the circuit is closed if it succeeds, and opened again otherwise.
+// If onStateChange is not nil, it is called on every state change (for example, to update metrics);
+// it should not block.
+func WithCircuitBreaker(threshold int, cooldown time.Duration, onStateChange func(from, to CircuitState)) Option {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return func(c *Client) {
+		c.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+			onChange:  onStateChange,
+		}
+	}
+}
+
+// WithConnectionRetry sets a function that decides if request should be retried once, immediately,
+// after failure to get a response. By default, IsConnectionError is used.
+// If f is nil, requests are not retried.
+func WithConnectionRetry(f func(err error) bool) Option {
+	return func(c *Client) {
+		c.connRetry = f
+	}
+}
+
+// WithPerAttemptTimeout sets timeout for each HTTP request attempt, in addition to the context deadline.
+// That way, a single stalled attempt does not consume the whole deadline, leaving time for retries.
+func WithPerAttemptTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.attemptTimeout = d
+	}
+}
+
+// WithClockSkewCorrection makes client detect local clock skew from API response Date headers
+// and adjust Message.Timestamp values accordingly.
+// That is useful on devices without reliable time synchronization.
+// The first message is sent without adjustment.
+func WithClockSkewCorrection() Option {
+	return func(c *Client) {
+		c.skewCorrection = true
+	}
+}
+
+// WithRequestHook adds a function that is called with every HTTP request before it is sent,
+// including connection retries. It may modify the request, for example, add headers.
+func WithRequestHook(f func(req *http.Request)) Option {
+	return func(c *Client) {
+		c.requestHooks = append(c.requestHooks, f)
+	}
+}
+
+// WithResponseHook adds a function that is called with every HTTP response or error,
+// including connection retries. It should not read or close response body.
+func WithResponseHook(f func(resp *http.Response, err error)) Option {
+	return func(c *Client) {
+		c.responseHooks = append(c.responseHooks, f)
+	}
+}
+
+// WithDebugDump writes dumps of every HTTP request and response to w.
+// Application token is masked. It is intended for diagnosing problems only.
+func WithDebugDump(w io.Writer) Option {
+	return func(c *Client) {
+		c.debugDump = w
+	}
+}
+
+// sendOptions holds per-call settings.
+type sendOptions struct {
+	retries        int
+	backoff        *Backoff
+	retryable      func(error) bool
+	attemptTimeout time.Duration
+	skipValidation bool
+	attachment     *attachment // set from message, not by options
+}
+
+// newSendOptions returns settings configured by given options.
+func newSendOptions(opts []SendOption) sendOptions {
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// SendOption configures a single SendMessage call.
+type SendOption func(*sendOptions)
+
+// WithRetries retries sending up to n times if error is retryable (see IsRetryable).
+// Delays between attempts grow exponentially from 5 seconds to 1 minute, with jitter; see WithBackoff.
+// Retrying stops when context is canceled.
+func WithRetries(n int) SendOption {
+	return func(o *sendOptions) {
+		o.retries = n
+	}
+}
+
+// withRetryable sets function that decides whether error should be retried, see RetryPolicy.
+func withRetryable(f func(error) bool) SendOption {
+	return func(o *sendOptions) {
+		o.retryable = f
+	}
+}
+
+// WithBackoff sets delays between attempts made with WithRetries.
+func WithBackoff(b Backoff) SendOption {
+	return func(o *sendOptions) {
+		o.backoff = &b
+	}
+}
+
+// WithAttemptTimeout overrides per-attempt timeout set by WithPerAttemptTimeout for a single call.
+func WithAttemptTimeout(d time.Duration) SendOption {
+	return func(o *sendOptions) {
+		o.attemptTimeout = d
+	}
+}
+
+// WithoutValidation disables message validation before sending (see Message.Validate).
+// Invalid messages are then rejected by Pushover API instead.
+func WithoutValidation() SendOption {
+	return func(o *sendOptions) {
+		o.skipValidation = true
+	}
+}
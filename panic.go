@@ -0,0 +1,42 @@
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// panicNotifyTimeout is a timeout for sending panic notification.
+const panicNotifyTimeout = 10 * time.Second
+
+// NotifyOnPanic returns a function that should be deferred at the beginning of a goroutine:
+//
+//	defer pushover.NotifyOnPanic(client, user)()
+//
+// If goroutine panics, that function recovers, sends a high-priority message with the panic value
+// and (truncated) stack trace to given user, and panics again with the same value.
+// Sending errors are ignored.
+func NotifyOnPanic(client Sender, user string) func() {
+	return func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		m := &Message{
+			User:     user,
+			Title:    "panic in " + filepath.Base(os.Args[0]),
+			Message:  truncate(fmt.Sprintf("panic: %v\n\n%s", r, debug.Stack()), MaxMessageLength),
+			Priority: HighPriority,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), panicNotifyTimeout)
+		_ = client.SendMessage(ctx, m)
+		cancel()
+
+		panic(r)
+	}
+}
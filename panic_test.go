@@ -0,0 +1,33 @@
+package pushover
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyOnPanic(t *testing.T) {
+	var text, priority string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		text = req.FormValue("message")
+		priority = req.FormValue("priority")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer NotifyOnPanic(c, testUser)()
+		panic("boom")
+	})
+
+	assert.True(t, strings.HasPrefix(text, "panic: boom\n\ngoroutine "), "%s", text)
+	assert.LessOrEqual(t, len([]rune(text)), MaxMessageLength)
+	assert.Equal(t, "1", priority)
+
+	text = ""
+	assert.NotPanics(t, func() {
+		defer NotifyOnPanic(c, testUser)()
+	})
+	assert.Empty(t, text)
+}
@@ -0,0 +1,101 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTokensExhausted is returned by TokenPool when monthly limits of all tokens are exhausted.
+var ErrTokensExhausted = errors.New("pushover: all application tokens are exhausted")
+
+// defaultTokenExhaustion is the time for which token is not used after rate limit error
+// without reset time.
+const defaultTokenExhaustion = time.Hour
+
+// TokenPool sends messages with several application tokens, rotating across them.
+// When Pushover API reports that token's monthly limit is exhausted,
+// the message is sent with the next token, and exhausted token is not used until limit reset.
+//
+// Messages with Message.Token set are sent with that token as is.
+type TokenPool struct {
+	Client *Client
+	Tokens []string
+
+	m         sync.Mutex
+	next      int
+	exhausted map[string]time.Time // token -> reset time
+}
+
+// SendMessage implements Sender.
+func (p *TokenPool) SendMessage(ctx context.Context, message *Message, opts ...SendOption) error {
+	_, err := p.SendMessageWithResponse(ctx, message, opts...)
+	return err
+}
+
+// SendMessageWithResponse sends given message with the next available token.
+func (p *TokenPool) SendMessageWithResponse(ctx context.Context, message *Message, opts ...SendOption) (*Response, error) {
+	if message.Token != "" {
+		return p.Client.SendMessageWithResponse(ctx, message, opts...)
+	}
+
+	err := ErrTokensExhausted
+	for range p.Tokens {
+		token := p.token()
+		if token == "" {
+			break
+		}
+
+		m := *message
+		m.Token = token
+		var res *Response
+		if res, err = p.Client.SendMessageWithResponse(ctx, &m, opts...); !isRateLimited(err) {
+			return res, err
+		}
+		p.markExhausted(token, err)
+	}
+	return nil, err
+}
+
+// token returns the next token that is not exhausted, or empty string.
+func (p *TokenPool) token() string {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	now := time.Now()
+	for range p.Tokens {
+		token := p.Tokens[p.next%len(p.Tokens)]
+		p.next = (p.next + 1) % len(p.Tokens)
+		if reset, ok := p.exhausted[token]; ok {
+			if now.Before(reset) {
+				continue
+			}
+			delete(p.exhausted, token)
+		}
+		return token
+	}
+	return ""
+}
+
+// markExhausted marks token as exhausted until limits reset time from rate limit error.
+func (p *TokenPool) markExhausted(token string, err error) {
+	reset := time.Now().Add(defaultTokenExhaustion)
+	var e *RateLimitError
+	if errors.As(err, &e) && !e.Reset.IsZero() {
+		reset = e.Reset
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.exhausted == nil {
+		p.exhausted = make(map[string]time.Time)
+	}
+	p.exhausted[token] = reset
+}
+
+// check interfaces
+var (
+	_ Sender = (*TokenPool)(nil)
+)
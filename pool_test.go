@@ -0,0 +1,46 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenPool(t *testing.T) {
+	const (
+		token1 = "aToken1Token1Token1Token1Token"
+		token2 = "aToken2Token2Token2Token2Token"
+	)
+
+	var tokens []string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		token := req.FormValue("token")
+		tokens = append(tokens, token)
+		if token == token1 {
+			rw.Header().Set("X-Limit-App-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			rw.WriteHeader(429)
+			rw.Write([]byte(`{"status":0,"errors":["application is over its limit"]}`))
+			return
+		}
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	p := &TokenPool{Client: c, Tokens: []string{token1, token2}}
+	ctx := context.Background()
+	m := &Message{User: testUser, Message: "message"}
+
+	require.NoError(t, p.SendMessage(ctx, m))
+	require.NoError(t, p.SendMessage(ctx, m))
+	assert.Equal(t, []string{token1, token2, token2}, tokens)
+	assert.Empty(t, m.Token)
+
+	p.markExhausted(token2, errors.New("test"))
+	err := p.SendMessage(ctx, m)
+	assert.Equal(t, ErrTokensExhausted, err)
+}
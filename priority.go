@@ -0,0 +1,59 @@
+package pushover
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Priority represents message priority.
+type Priority int
+
+// Message priority.
+const (
+	LowestPriority    Priority = -2 // lowest priority, no notification
+	LowPriority       Priority = -1 // low priority, no sound and vibration
+	NormalPriority    Priority = 0  // normal priority, default
+	HighPriority      Priority = 1  // high priority, always with sound and vibration
+	EmergencyPriority Priority = 2  // emergency priority, requires acknowledge
+)
+
+var priorityNames = map[Priority]string{
+	LowestPriority:    "lowest",
+	LowPriority:       "low",
+	NormalPriority:    "normal",
+	HighPriority:      "high",
+	EmergencyPriority: "emergency",
+}
+
+// String returns priority name, or number for invalid priorities.
+func (p Priority) String() string {
+	if s, ok := priorityNames[p]; ok {
+		return s
+	}
+	return strconv.Itoa(int(p))
+}
+
+// IsValid returns true if p is one of the defined priorities.
+func (p Priority) IsValid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+// ParsePriority parses priority name (case-insensitive, as returned by Priority.String) or number.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for p, name := range priorityNames {
+		if s == name {
+			return p, nil
+		}
+	}
+
+	if i, err := strconv.Atoi(s); err == nil {
+		if p := Priority(i); p.IsValid() {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("pushover: invalid priority %q", s)
+}
@@ -0,0 +1,34 @@
+package pushover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriority(t *testing.T) {
+	for _, p := range []Priority{LowestPriority, LowPriority, NormalPriority, HighPriority, EmergencyPriority} {
+		assert.True(t, p.IsValid())
+
+		actual, err := ParsePriority(p.String())
+		require.NoError(t, err)
+		assert.Equal(t, p, actual)
+	}
+
+	p, err := ParsePriority(" High ")
+	require.NoError(t, err)
+	assert.Equal(t, HighPriority, p)
+
+	p, err = ParsePriority("-2")
+	require.NoError(t, err)
+	assert.Equal(t, LowestPriority, p)
+
+	assert.False(t, Priority(3).IsValid())
+	assert.Equal(t, "3", Priority(3).String())
+
+	_, err = ParsePriority("3")
+	require.EqualError(t, err, `pushover: invalid priority "3"`)
+	_, err = ParsePriority("urgent")
+	require.EqualError(t, err, `pushover: invalid priority "urgent"`)
+}
@@ -0,0 +1,162 @@
+// Package pushoverhttp provides HTTP middleware that sends Pushover alerts.
+package pushoverhttp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AlekSi/pushover"
+)
+
+// Default option values.
+const (
+	DefaultThreshold   = 0.5
+	DefaultMinRequests = 10
+	DefaultWindow      = time.Minute
+)
+
+// sendTimeout is a timeout for sending an alert.
+const sendTimeout = 30 * time.Second
+
+// Options configure Middleware.
+type Options struct {
+	User        string        // user/group key to alert, required
+	Title       string        // alert title, defaults to application name
+	Threshold   float64       // 5xx responses rate in window that triggers alert, defaults to DefaultThreshold
+	MinRequests int           // minimal number of requests in window to trigger alert, defaults to DefaultMinRequests
+	Window      time.Duration // window length, defaults to DefaultWindow
+
+	// OnError, if set, is called with alert sending errors.
+	OnError func(err error)
+}
+
+// Middleware returns HTTP middleware that counts 5xx responses and sends an alert to Pushover
+// when their rate exceeds the threshold within a window.
+// At most one alert is sent per window. Alerts are sent in the background and do not delay responses.
+func Middleware(client pushover.Sender, opts Options) func(http.Handler) http.Handler {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = DefaultMinRequests
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+
+	c := &counter{
+		client: client,
+		opts:   opts,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rec := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+			defer func() {
+				c.add(rec.status >= 500)
+			}()
+
+			next.ServeHTTP(rec, req)
+		})
+	}
+}
+
+// counter counts requests and errors within a fixed window.
+type counter struct {
+	client pushover.Sender
+	opts   Options
+
+	m           sync.Mutex
+	windowStart time.Time
+	requests    int
+	errors      int
+	alerted     bool
+}
+
+func (c *counter) add(isError bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.windowStart) >= c.opts.Window {
+		c.windowStart = now
+		c.requests, c.errors = 0, 0
+		c.alerted = false
+	}
+
+	c.requests++
+	if isError {
+		c.errors++
+	}
+
+	if c.alerted || c.requests < c.opts.MinRequests {
+		return
+	}
+	if rate := float64(c.errors) / float64(c.requests); rate >= c.opts.Threshold {
+		c.alerted = true
+		go c.alert(c.errors, c.requests, now.Sub(c.windowStart))
+	}
+}
+
+func (c *counter) alert(failed, requests int, d time.Duration) {
+	m := &pushover.Message{
+		User:     c.opts.User,
+		Title:    c.opts.Title,
+		Message:  fmt.Sprintf("%d of %d requests failed with 5xx in %s.", failed, requests, d.Round(time.Second)),
+		Priority: pushover.HighPriority,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	if err := c.client.SendMessage(ctx, m); err != nil && c.opts.OnError != nil {
+		c.opts.OnError(err)
+	}
+}
+
+// statusRecorder records response status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if underlying ResponseWriter supports it.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		r.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if underlying ResponseWriter supports it.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("pushoverhttp: %T does not implement http.Hijacker", r.ResponseWriter)
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns underlying ResponseWriter for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
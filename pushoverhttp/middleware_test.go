@@ -0,0 +1,104 @@
+package pushoverhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/pushover"
+)
+
+func TestMiddleware(t *testing.T) {
+	alerts := make(chan string, 10)
+	api := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		alerts <- req.FormValue("message")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}))
+	defer api.Close()
+
+	client, err := pushover.NewClient("token", pushover.WithBaseURL(api.URL))
+	require.NoError(t, err)
+
+	mw := Middleware(client, Options{
+		User:        "uQiRzpo4DXghDmr9QzzfQu27cmVRsG",
+		MinRequests: 4,
+	})
+	h := mw(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/fail" {
+			http.Error(rw, "fail", 500)
+		}
+	}))
+
+	for _, path := range []string{"/ok", "/fail", "/ok", "/fail", "/fail", "/fail"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	}
+
+	select {
+	case alert := <-alerts:
+		assert.Contains(t, alert, "2 of 4 requests failed with 5xx")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert")
+	}
+
+	// only one alert per window
+	select {
+	case alert := <-alerts:
+		t.Fatalf("unexpected alert %q", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMiddlewareWriterInterfaces(t *testing.T) {
+	client, err := pushover.NewClient("token")
+	require.NoError(t, err)
+	mw := Middleware(client, Options{User: "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"})
+
+	t.Run("Flusher", func(t *testing.T) {
+		s := httptest.NewServer(mw(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Write([]byte("chunk"))
+			rw.(http.Flusher).Flush()
+		})))
+		defer s.Close()
+
+		resp, err := http.Get(s.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "chunk", string(b))
+		assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+	})
+
+	t.Run("Hijacker", func(t *testing.T) {
+		s := httptest.NewServer(mw(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			conn, buf, err := rw.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			defer conn.Close()
+			buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+			buf.Flush()
+		})))
+		defer s.Close()
+
+		resp, err := http.Get(s.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "hijacked", string(b))
+	})
+
+	t.Run("NotHijacker", func(t *testing.T) {
+		h := mw(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _, err := rw.(http.Hijacker).Hijack()
+			assert.Error(t, err)
+			rw.(http.Flusher).Flush()
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+}
@@ -0,0 +1,126 @@
+// Package pushovertest provides utilities for testing code that uses pushover package.
+package pushovertest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AlekSi/pushover"
+)
+
+// Notifier is an interface implemented by *pushover.Client and ChaosClient.
+type Notifier = pushover.Sender
+
+// Errors injected by ChaosClient; use errors.Is to check for them.
+var (
+	ErrInjected    = errors.New("pushovertest: injected error")
+	ErrRateLimited = errors.New("pushovertest: injected rate limit error")
+)
+
+// ChaosClient wraps Notifier and injects latency and errors.
+// If Next is nil, messages are not sent anywhere.
+//
+// Injected errors look like ones returned by *pushover.Client:
+// ErrInjected is wrapped in *pushover.TemporaryError, and ErrRateLimited
+// is a *pushover.TemporaryError wrapping *pushover.RateLimitError for HTTP status 429.
+//
+// ChaosClient is safe for concurrent use.
+type ChaosClient struct {
+	Next Notifier
+
+	Latency       time.Duration // fixed latency added to every call
+	LatencyJitter time.Duration // random latency in [0, LatencyJitter) added to every call
+	ErrorRate     float64       // fraction of calls failing with ErrInjected, from 0 to 1
+	RateLimitRate float64       // fraction of calls failing with ErrRateLimited, from 0 to 1
+
+	// Rand, if set, is used as a source of randomness for reproducible tests.
+	Rand *rand.Rand
+
+	m sync.Mutex
+}
+
+// SendMessage implements Notifier.
+func (c *ChaosClient) SendMessage(ctx context.Context, message *pushover.Message, opts ...pushover.SendOption) error {
+	delay, r := c.random()
+
+	if delay := c.Latency + delay; delay > 0 {
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+
+	switch {
+	case r < c.ErrorRate:
+		return &pushover.TemporaryError{Err: ErrInjected}
+	case r < c.ErrorRate+c.RateLimitRate:
+		return &pushover.TemporaryError{Err: newRateLimitError(time.Now())}
+	}
+
+	if c.Next == nil {
+		return nil
+	}
+	return c.Next.SendMessage(ctx, message, opts...)
+}
+
+// rateLimitError is *pushover.RateLimitError that matches ErrRateLimited.
+type rateLimitError struct {
+	*pushover.RateLimitError
+}
+
+// newRateLimitError returns injected rate limit error with limits reset at the start of the next month.
+func newRateLimitError(now time.Time) error {
+	now = now.UTC()
+	return &rateLimitError{
+		RateLimitError: &pushover.RateLimitError{
+			Limits: pushover.Limits{
+				Limit:     10000,
+				Remaining: 0,
+				Reset:     time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			Err: &pushover.APIError{
+				HTTPStatus: http.StatusTooManyRequests,
+				Messages:   []string{ErrRateLimited.Error()},
+			},
+		},
+	}
+}
+
+// Unwrap returns underlying *pushover.RateLimitError.
+func (e *rateLimitError) Unwrap() error {
+	return e.RateLimitError
+}
+
+// Is returns true for ErrRateLimited.
+func (e *rateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// random returns random latency jitter and a random number in [0, 1).
+func (c *ChaosClient) random() (time.Duration, float64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.Rand == nil {
+		c.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	var delay time.Duration
+	if c.LatencyJitter > 0 {
+		delay = time.Duration(c.Rand.Int63n(int64(c.LatencyJitter)))
+	}
+	return delay, c.Rand.Float64()
+}
+
+// check interfaces
+var (
+	_ Notifier = (*pushover.Client)(nil)
+	_ Notifier = (*ChaosClient)(nil)
+)
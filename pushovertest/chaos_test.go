@@ -0,0 +1,52 @@
+package pushovertest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/pushover"
+)
+
+func TestChaosClient(t *testing.T) {
+	c := &ChaosClient{
+		ErrorRate:     0.2,
+		RateLimitRate: 0.3,
+		Rand:          rand.New(rand.NewSource(1)),
+	}
+
+	var ok, injected, rateLimited int
+	for i := 0; i < 1000; i++ {
+		err := c.SendMessage(context.Background(), &pushover.Message{})
+		switch {
+		case err == nil:
+			ok++
+			continue
+		case errors.Is(err, ErrInjected):
+			injected++
+		case errors.Is(err, ErrRateLimited):
+			rateLimited++
+
+			var rle *pushover.RateLimitError
+			require.ErrorAs(t, err, &rle)
+			assert.Equal(t, 0, rle.Remaining)
+			assert.True(t, rle.Reset.After(time.Now()))
+			assert.Equal(t, http.StatusTooManyRequests, rle.StatusCode())
+		default:
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		var te *pushover.TemporaryError
+		assert.ErrorAs(t, err, &te)
+		assert.True(t, pushover.IsRetryable(err))
+	}
+	assert.InDelta(t, 500, ok, 50)
+	assert.InDelta(t, 200, injected, 50)
+	assert.InDelta(t, 300, rateLimited, 50)
+}
@@ -0,0 +1,258 @@
+// Package queue provides persistent on-disk queue of Pushover messages.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlekSi/pushover"
+)
+
+// Default option values.
+const (
+	DefaultInterval = 10 * time.Second // interval between attempts to deliver queued messages
+	DefaultMaxDelay = 10 * time.Minute // time after which messages are delivered regardless of priority
+)
+
+// failedDir is a subdirectory for messages that were rejected by Pushover API.
+const failedDir = "failed"
+
+// ErrAttachment is returned by Enqueue for messages with attachments, which can't be persisted.
+var ErrAttachment = errors.New("queue: messages with attachments can't be queued")
+
+// Queue journals messages to files in a directory and delivers them,
+// retrying temporary failures across process restarts.
+// High and emergency priority messages are delivered before others;
+// messages queued for longer than MaxDelay are delivered in order regardless of priority.
+// A message file is deleted only after successful delivery.
+// Messages rejected by Pushover API (see pushover.FatalError) are moved to "failed" subdirectory.
+//
+// Queue is safe for concurrent use; Run should be called only once at a time for a given directory.
+type Queue struct {
+	Dir      string          // directory for message files, created if needed
+	Sender   pushover.Sender // used to deliver messages
+	Interval time.Duration   // defaults to DefaultInterval
+	MaxDelay time.Duration   // starvation protection for lower priorities, defaults to DefaultMaxDelay
+
+	// OnError, if set, is called for every delivery error.
+	OnError func(err error)
+
+	seq uint64
+	m   sync.Mutex // serializes Dir creation
+}
+
+// Enqueue durably writes message to the queue directory.
+func (q *Queue) Enqueue(message *pushover.Message) error {
+	if message.Attachment != nil {
+		return ErrAttachment
+	}
+
+	b, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	q.m.Lock()
+	err = os.MkdirAll(q.Dir, 0o700)
+	q.m.Unlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(q.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(b); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// name encodes enqueue time for ordering and priority for dispatching without reading file
+	name := fmt.Sprintf("%020d-%010d-%d.json", time.Now().UnixNano(), atomic.AddUint64(&q.seq, 1), message.Priority-pushover.LowestPriority)
+	if err = os.Rename(f.Name(), filepath.Join(q.Dir, name)); err != nil {
+		return err
+	}
+
+	// make rename durable
+	return syncDir(q.Dir)
+}
+
+// syncDir flushes directory entries to disk.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Run delivers queued messages until ctx is canceled.
+// The first delivery attempt is made immediately.
+func (q *Queue) Run(ctx context.Context) error {
+	interval := q.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	for {
+		if err := q.Deliver(ctx); err != nil && q.OnError != nil && ctx.Err() == nil {
+			q.OnError(err)
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Deliver sends queued messages, high priority and delayed ones first.
+// It stops on the first temporary error and returns it; the message is retried by the next call.
+// It also stops if a delivered message can't be removed; such message may be delivered again.
+func (q *Queue) Deliver(ctx context.Context) error {
+	names, err := q.names()
+	if err != nil {
+		return err
+	}
+	names = q.dispatchOrder(names, time.Now())
+
+	for _, name := range names {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		path := filepath.Join(q.Dir, name)
+		err := q.send(ctx, path)
+		if err == nil {
+			// the message is delivered, so it must not be moved aside as failed
+			if err = os.Remove(path); err != nil {
+				return fmt.Errorf("queue: message %s was delivered, but not removed: %w", name, err)
+			}
+			continue
+		}
+
+		if pushover.IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		// move rejected or corrupted message aside so it does not block the queue
+		if mkErr := os.MkdirAll(filepath.Join(q.Dir, failedDir), 0o700); mkErr != nil {
+			return mkErr
+		}
+		if mvErr := os.Rename(path, filepath.Join(q.Dir, failedDir, name)); mvErr != nil {
+			return mvErr
+		}
+		if q.OnError != nil {
+			q.OnError(fmt.Errorf("queue: message %s failed: %w", name, err))
+		}
+	}
+
+	return nil
+}
+
+// send reads message from file at given path and sends it.
+func (q *Queue) send(ctx context.Context, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var m pushover.Message
+	if err = json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	return q.Sender.SendMessage(ctx, &m)
+}
+
+// Len returns the number of queued messages.
+func (q *Queue) Len() (int, error) {
+	names, err := q.names()
+	return len(names), err
+}
+
+// dispatchOrder returns sorted names of message files reordered for delivery at given time:
+// high and emergency priority messages and messages queued longer than MaxDelay first,
+// then the rest; both groups keep enqueueing order.
+func (q *Queue) dispatchOrder(names []string, now time.Time) []string {
+	maxDelay := q.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+
+	res := make([]string, 0, len(names))
+	var rest []string
+	for _, name := range names {
+		queued, priority := parseName(name)
+		if priority >= pushover.HighPriority || now.Sub(queued) > maxDelay {
+			res = append(res, name)
+			continue
+		}
+		rest = append(rest, name)
+	}
+	return append(res, rest...)
+}
+
+// parseName returns enqueue time and priority encoded in message file name.
+// Priority is normal for names without it.
+func parseName(name string) (time.Time, pushover.Priority) {
+	parts := strings.Split(strings.TrimSuffix(name, ".json"), "-")
+
+	var queued time.Time
+	if ns, err := strconv.ParseInt(parts[0], 10, 64); err == nil {
+		queued = time.Unix(0, ns)
+	}
+
+	priority := pushover.NormalPriority
+	if len(parts) > 2 {
+		if p, err := strconv.Atoi(parts[2]); err == nil {
+			priority = pushover.Priority(p) + pushover.LowestPriority
+		}
+	}
+	return queued, priority
+}
+
+// names returns sorted names of queued message files.
+func (q *Queue) names() ([]string, error) {
+	entries, err := os.ReadDir(q.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, e := range entries {
+		if name := e.Name(); e.Type().IsRegular() && strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, ".") {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
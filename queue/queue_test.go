@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlekSi/pushover"
+)
+
+// senderFunc is a pushover.Sender implemented by a function.
+type senderFunc func(ctx context.Context, message *pushover.Message) error
+
+func (f senderFunc) SendMessage(ctx context.Context, message *pushover.Message, opts ...pushover.SendOption) error {
+	return f(ctx, message)
+}
+
+func TestQueue(t *testing.T) {
+	var sent []string
+	fail := errors.New("temporary")
+	sender := senderFunc(func(ctx context.Context, message *pushover.Message) error {
+		switch message.Message {
+		case "temporary":
+			if fail != nil {
+				return &pushover.TemporaryError{Err: fail}
+			}
+		case "fatal":
+			return &pushover.FatalError{Err: errors.New("fatal")}
+		}
+		sent = append(sent, message.Message)
+		return nil
+	})
+
+	var errs []error
+	q := &Queue{
+		Dir:     filepath.Join(t.TempDir(), "queue"),
+		Sender:  sender,
+		OnError: func(err error) { errs = append(errs, err) },
+	}
+
+	for _, text := range []string{"first", "fatal", "temporary", "last"} {
+		require.NoError(t, q.Enqueue(&pushover.Message{User: "user", Message: text, Priority: pushover.HighPriority}))
+	}
+	assert.Equal(t, ErrAttachment, q.Enqueue(&pushover.Message{Attachment: strings.NewReader("image")}))
+
+	ctx := context.Background()
+
+	// stops on temporary error; fatal message is moved aside
+	err := q.Deliver(ctx)
+	require.Error(t, err)
+	assert.Equal(t, []string{"first"}, sent)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "fatal")
+	n, err := q.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	failed, err := os.ReadDir(filepath.Join(q.Dir, "failed"))
+	require.NoError(t, err)
+	assert.Len(t, failed, 1)
+
+	// queue survives "restart"
+	fail = nil
+	q = &Queue{Dir: q.Dir, Sender: sender}
+	require.NoError(t, q.Deliver(ctx))
+	assert.Equal(t, []string{"first", "temporary", "last"}, sent)
+	n, err = q.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestQueueRemoveError(t *testing.T) {
+	q := &Queue{Dir: t.TempDir()}
+	require.NoError(t, q.Enqueue(&pushover.Message{User: "user", Message: "message"}))
+	names, err := q.names()
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+	path := filepath.Join(q.Dir, names[0])
+
+	// replace delivered message file with non-empty directory that can't be removed
+	q.Sender = senderFunc(func(ctx context.Context, message *pushover.Message) error {
+		require.NoError(t, os.Remove(path))
+		require.NoError(t, os.Mkdir(path, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(path, "file"), nil, 0o600))
+		return nil
+	})
+
+	err = q.Deliver(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was delivered, but not removed")
+	assert.NoDirExists(t, filepath.Join(q.Dir, "failed"))
+	assert.DirExists(t, path)
+}
+
+func TestQueuePriorities(t *testing.T) {
+	var sent []string
+	q := &Queue{
+		Dir: t.TempDir(),
+		Sender: senderFunc(func(ctx context.Context, message *pushover.Message) error {
+			sent = append(sent, message.Message)
+			return nil
+		}),
+	}
+
+	for _, m := range []*pushover.Message{
+		{Message: "low", Priority: pushover.LowPriority},
+		{Message: "normal"},
+		{Message: "emergency", Priority: pushover.EmergencyPriority},
+		{Message: "high", Priority: pushover.HighPriority},
+	} {
+		require.NoError(t, q.Enqueue(m))
+	}
+
+	require.NoError(t, q.Deliver(context.Background()))
+	assert.Equal(t, []string{"emergency", "high", "low", "normal"}, sent)
+}
+
+func TestDispatchOrder(t *testing.T) {
+	now := time.Unix(0, 1_000_000_000_000)
+	name := func(age time.Duration, seq int, p pushover.Priority) string {
+		return fmt.Sprintf("%020d-%010d-%d.json", now.Add(-age).UnixNano(), seq, p-pushover.LowestPriority)
+	}
+
+	starving := name(time.Hour, 1, pushover.LowestPriority)
+	normal := name(time.Minute, 2, pushover.NormalPriority)
+	high := name(time.Second, 3, pushover.HighPriority)
+	old := fmt.Sprintf("%020d-%010d.json", now.UnixNano(), 4)
+
+	q := &Queue{MaxDelay: 30 * time.Minute}
+	actual := q.dispatchOrder([]string{starving, normal, high, old}, now)
+	assert.Equal(t, []string{starving, high, normal, old}, actual)
+}
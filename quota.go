@@ -0,0 +1,45 @@
+package pushover
+
+// updateLimits remembers limits of given application token from response headers
+// and calls quota warning callback when remaining messages count drops below threshold.
+// Callback is called once per token until remaining count is restored (typically, after monthly reset).
+func (c *Client) updateLimits(token string, l Limits) {
+	if l.Limit == 0 {
+		return // no headers
+	}
+
+	c.limitsM.Lock()
+	if c.limits == nil {
+		c.limits = make(map[string]Limits)
+		c.quotaWarned = make(map[string]bool)
+	}
+	c.limits[token] = l
+	warn := c.quotaWarning != nil && l.Remaining < c.quotaThreshold && !c.quotaWarned[token]
+	if warn {
+		c.quotaWarned[token] = true
+	}
+	if l.Remaining >= c.quotaThreshold {
+		delete(c.quotaWarned, token)
+	}
+	c.limitsM.Unlock()
+
+	if warn {
+		c.quotaWarning(l)
+	}
+}
+
+// Limits returns application's monthly message limits from the last API response with them.
+// Zero value is returned if there were no such responses yet.
+// Only requests made with the client's token are taken into account; see TokenLimits.
+func (c *Client) Limits() Limits {
+	return c.TokenLimits(c.appToken)
+}
+
+// TokenLimits is like Limits, but for given application token
+// (for example, used by Message.Token or TokenPool).
+func (c *Client) TokenLimits(token string) Limits {
+	c.limitsM.Lock()
+	defer c.limitsM.Unlock()
+
+	return c.limits[token]
+}
@@ -0,0 +1,69 @@
+package pushover
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaWarning(t *testing.T) {
+	var remaining int32 = 12
+	var warnings []int
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Limit-App-Limit", "10000")
+		rw.Header().Set("X-Limit-App-Remaining", strconv.Itoa(int(atomic.AddInt32(&remaining, -1))))
+		rw.Header().Set("X-Limit-App-Reset", "1393653600")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithQuotaWarning(10, func(l Limits) {
+		warnings = append(warnings, l.Remaining)
+	}))
+
+	ctx := context.Background()
+	assert.Zero(t, c.Limits())
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, c.Send(ctx, testUser, "message"))
+	}
+	assert.Equal(t, []int{9}, warnings)
+	assert.Equal(t, 8, c.Limits().Remaining)
+	assert.Equal(t, 10000, c.Limits().Limit)
+
+	// monthly reset
+	atomic.StoreInt32(&remaining, 10001)
+	require.NoError(t, c.Send(ctx, testUser, "message"))
+	atomic.StoreInt32(&remaining, 6)
+	require.NoError(t, c.Send(ctx, testUser, "message"))
+	assert.Equal(t, []int{9, 5}, warnings)
+}
+
+func TestQuotaWarningTokens(t *testing.T) {
+	const otherToken = "a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5"
+	remaining := map[string]int{"azGDORePK8gMaC0QOYAMyEEuzJnyUi": 100, otherToken: 9}
+	var warnings []int
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		token := req.FormValue("token")
+		remaining[token]--
+		rw.Header().Set("X-Limit-App-Limit", "10000")
+		rw.Header().Set("X-Limit-App-Remaining", strconv.Itoa(remaining[token]))
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithQuotaWarning(10, func(l Limits) {
+		warnings = append(warnings, l.Remaining)
+	}))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "message"}))
+		require.NoError(t, c.SendMessage(ctx, &Message{User: testUser, Message: "message", Token: otherToken}))
+	}
+
+	// responses for the client's token do not reset the warning for the other one
+	assert.Equal(t, []int{8}, warnings)
+	assert.Equal(t, 98, c.Limits().Remaining)
+	assert.Equal(t, 7, c.TokenLimits(otherToken).Remaining)
+	assert.Zero(t, c.TokenLimits("unknown"))
+}
@@ -0,0 +1,75 @@
+package pushover
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Receipt returns the status of emergency priority message with given receipt.
+//
+// See https://pushover.net/api/receipts.
+func (c *Client) Receipt(ctx context.Context, receipt string) (*Response, error) {
+	return c.receipt(ctx, c.appToken, receipt)
+}
+
+// receipt returns the status of emergency priority message with given receipt,
+// using given application token that was used to send it.
+func (c *Client) receipt(ctx context.Context, token, receipt string) (*Response, error) {
+	data := make(url.Values)
+	data.Set("token", token)
+
+	return c.sendRequest(ctx, "GET", receiptsPath+url.PathEscape(receipt)+".json", data, sendOptions{})
+}
+
+// emergencyKey returns key of outstanding emergency messages for given message and tag.
+// Messages are duplicates only if they are sent with the same token to the same recipient and devices.
+func (c *Client) emergencyKey(message *Message, tag string) string {
+	return strings.Join([]string{c.messageToken(message), message.User, strings.Join(message.Devices, ","), tag}, "\x00")
+}
+
+// outstandingEmergency returns the response of previously sent emergency message
+// with any of the same tags, token and recipient if it is neither acknowledged nor expired yet, or nil.
+// Failures to get receipt status are ignored, so a new message is sent in that case.
+func (c *Client) outstandingEmergency(ctx context.Context, message *Message) *Response {
+	token := c.messageToken(message)
+	for _, tag := range message.Tags {
+		key := c.emergencyKey(message, tag)
+
+		c.receiptsM.Lock()
+		receipt := c.receipts[key]
+		c.receiptsM.Unlock()
+		if receipt == "" {
+			continue
+		}
+
+		res, err := c.receipt(ctx, token, receipt)
+		if err != nil {
+			continue
+		}
+		if res.Acknowledged == 0 && res.Expired == 0 {
+			return &Response{Status: 1, Request: res.Request, Receipt: receipt}
+		}
+
+		c.receiptsM.Lock()
+		if c.receipts[key] == receipt {
+			delete(c.receipts, key)
+		}
+		c.receiptsM.Unlock()
+	}
+
+	return nil
+}
+
+// trackEmergency remembers receipt of given emergency message for each of its tags.
+func (c *Client) trackEmergency(message *Message, receipt string) {
+	c.receiptsM.Lock()
+	defer c.receiptsM.Unlock()
+
+	if c.receipts == nil {
+		c.receipts = make(map[string]string)
+	}
+	for _, tag := range message.Tags {
+		c.receipts[c.emergencyKey(message, tag)] = receipt
+	}
+}
@@ -0,0 +1,88 @@
+package pushover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrUnknownRecipient is returned by StaticResolver for unknown aliases.
+var ErrUnknownRecipient = errors.New("pushover: unknown recipient")
+
+// Recipient represents a resolved message recipient.
+type Recipient struct {
+	User    string   `json:"user"`              // user/group key
+	Devices []string `json:"devices,omitempty"` // device names, all user's devices if empty
+}
+
+// RecipientResolver resolves recipient aliases (such as team or person names) to user keys and devices.
+// Implementations may use directory services such as LDAP; see WithRecipientResolver.
+type RecipientResolver interface {
+	ResolveRecipient(ctx context.Context, alias string) (*Recipient, error)
+}
+
+// StaticResolver is a RecipientResolver backed by a map from aliases to recipients.
+type StaticResolver map[string]Recipient
+
+// LoadStaticResolver reads StaticResolver from JSON file with an object mapping aliases to recipients:
+//
+//	{"ops": {"user": "uQiRzpo4DXghDmr9QzzfQu27cmVRsG", "devices": ["iphone"]}}
+func LoadStaticResolver(filename string) (StaticResolver, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var res StaticResolver
+	if err = json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("pushover: failed to parse %s: %w", filename, err)
+	}
+	return res, nil
+}
+
+// ResolveRecipient implements RecipientResolver.
+func (r StaticResolver) ResolveRecipient(ctx context.Context, alias string) (*Recipient, error) {
+	res, ok := r[alias]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownRecipient, alias)
+	}
+	return &res, nil
+}
+
+// resolveRecipient returns a copy of the message with alias in Message.User resolved,
+// or message as is if Message.User is empty or is a list of valid user keys.
+func (c *Client) resolveRecipient(ctx context.Context, message *Message) (*Message, error) {
+	if message.User == "" || isUserKeyList(message.User) {
+		return message, nil
+	}
+
+	r, err := c.resolver.ResolveRecipient(ctx, message.User)
+	if err != nil {
+		return nil, err
+	}
+
+	m := *message
+	m.User = r.User
+	if len(m.Devices) == 0 {
+		m.Devices = r.Devices
+	}
+	return &m, nil
+}
+
+// isUserKeyList returns true if s is a comma-separated list of valid user keys.
+func isUserKeyList(s string) bool {
+	for _, u := range strings.Split(s, ",") {
+		if !IsValidUserKey(u) {
+			return false
+		}
+	}
+	return true
+}
+
+// check interfaces
+var (
+	_ RecipientResolver = StaticResolver(nil)
+)
@@ -0,0 +1,55 @@
+package pushover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStaticResolver(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "recipients.json")
+	err := os.WriteFile(filename, []byte(`{"ops": {"user": "`+testUser+`", "devices": ["iphone"]}}`), 0o644)
+	require.NoError(t, err)
+
+	r, err := LoadStaticResolver(filename)
+	require.NoError(t, err)
+
+	res, err := r.ResolveRecipient(context.Background(), "ops")
+	require.NoError(t, err)
+	assert.Equal(t, &Recipient{User: testUser, Devices: []string{"iphone"}}, res)
+
+	_, err = r.ResolveRecipient(context.Background(), "dev")
+	require.EqualError(t, err, `pushover: unknown recipient "dev"`)
+	assert.True(t, errors.Is(err, ErrUnknownRecipient))
+}
+
+func TestRecipientResolver(t *testing.T) {
+	var user, device string
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		user, device = req.FormValue("user"), req.FormValue("device")
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	}, WithRecipientResolver(StaticResolver{
+		"ops": {User: testUser, Devices: []string{"iphone"}},
+	}))
+
+	ctx := context.Background()
+
+	require.NoError(t, c.Send(ctx, "ops", "message"))
+	assert.Equal(t, testUser, user)
+	assert.Equal(t, "iphone", device)
+
+	require.NoError(t, c.Send(ctx, testUser, "message"))
+	assert.Equal(t, testUser, user)
+	assert.Empty(t, device)
+
+	err := c.Send(ctx, "dev", "message")
+	assert.True(t, errors.Is(err, ErrUnknownRecipient))
+	var fatal *FatalError
+	assert.True(t, errors.As(err, &fatal))
+}
@@ -0,0 +1,72 @@
+package pushover
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Response represents successful Pushover API response.
+type Response struct {
+	Status  int    `json:"status"`            // always 1 for successful responses
+	Request string `json:"request"`           // request ID
+	Receipt string `json:"receipt,omitempty"` // receipt for emergency priority messages
+
+	// for users/validate.json only
+	Group    int      `json:"group,omitempty"`    // 1 for group keys
+	Devices  []string `json:"devices,omitempty"`  // user's active devices
+	Licenses []string `json:"licenses,omitempty"` // user's licensed platforms
+
+	// for receipts only, see Client.Receipt
+	Acknowledged   int   `json:"acknowledged,omitempty"`    // 1 if emergency message was acknowledged
+	AcknowledgedAt int64 `json:"acknowledged_at,omitempty"` // Unix timestamp of acknowledgement
+	Expired        int   `json:"expired,omitempty"`         // 1 if emergency message retries expired
+	ExpiresAt      int64 `json:"expires_at,omitempty"`      // Unix timestamp of retries expiration
+
+	// request timing, set by the client
+	Start    time.Time     `json:"-"` // local time when request was started
+	Duration time.Duration `json:"-"` // request duration, including connection error retry
+	Date     time.Time     `json:"-"` // server time from Date header, zero if absent
+}
+
+// apiResponse represents any Pushover API response.
+type apiResponse struct {
+	Response
+	Errors         []string        `json:"errors,omitempty"`
+	ErrorsWithKeys json.RawMessage `json:"errors_with_keys,omitempty"`
+}
+
+// fields returns decoded "errors_with_keys" field, or nil if it is absent or has unexpected format.
+func (r *apiResponse) fields() map[string][]string {
+	if len(r.ErrorsWithKeys) == 0 {
+		return nil
+	}
+
+	var res map[string][]string
+	if err := json.Unmarshal(r.ErrorsWithKeys, &res); err != nil {
+		return nil
+	}
+	return res
+}
+
+// Limits represents application's monthly message limits.
+//
+// See https://pushover.net/api#limits.
+type Limits struct {
+	Limit     int       // total number of messages per month
+	Remaining int       // number of messages remaining this month
+	Reset     time.Time // time when remaining count is reset to limit
+}
+
+// parseLimits returns limits from response headers.
+// Absent or invalid values are left zero.
+func parseLimits(h http.Header) Limits {
+	var l Limits
+	l.Limit, _ = strconv.Atoi(h.Get("X-Limit-App-Limit"))
+	l.Remaining, _ = strconv.Atoi(h.Get("X-Limit-App-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-Limit-App-Reset"), 10, 64); err == nil {
+		l.Reset = time.Unix(reset, 0)
+	}
+	return l
+}
@@ -0,0 +1,47 @@
+package pushover
+
+import "context"
+
+// Sender is an interface for sending messages.
+// It is implemented by *Client; downstream code can accept it to substitute mocks
+// or fan-out implementations in tests.
+type Sender interface {
+	SendMessage(ctx context.Context, message *Message, opts ...SendOption) error
+}
+
+// SendResult represents the result of sending a message by background sender.
+type SendResult struct {
+	Message  *Message  // sent message
+	Response *Response // nil on failure
+	Err      error     // nil on success
+}
+
+// StartSender starts a goroutine that sends messages received from the returned messages channel one by one,
+// and emits a result for each of them to the returned results channel.
+// Caller should receive all results.
+//
+// Caller should close the messages channel when done; the results channel is closed after that.
+// Once ctx is canceled, remaining messages are not sent; results with context error are emitted for them.
+func (c *Client) StartSender(ctx context.Context) (chan<- *Message, <-chan SendResult) {
+	messages := make(chan *Message)
+	results := make(chan SendResult)
+
+	go func() {
+		defer close(results)
+
+		for m := range messages {
+			res := SendResult{Message: m}
+			if res.Err = ctx.Err(); res.Err == nil {
+				res.Response, res.Err = c.SendMessageWithResponse(ctx, m)
+			}
+			results <- res
+		}
+	}()
+
+	return messages, results
+}
+
+// check interfaces
+var (
+	_ Sender = (*Client)(nil)
+)
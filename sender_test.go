@@ -0,0 +1,37 @@
+package pushover
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartSender(t *testing.T) {
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, results := c.StartSender(ctx)
+
+	m := &Message{User: testUser, Message: "message"}
+	messages <- m
+	res := <-results
+	require.NoError(t, res.Err)
+	assert.Equal(t, m, res.Message)
+	assert.Equal(t, "r", res.Response.Request)
+
+	cancel()
+	messages <- m
+	res = <-results
+	assert.Equal(t, context.Canceled, res.Err)
+
+	close(messages)
+	_, ok := <-results
+	assert.False(t, ok)
+}
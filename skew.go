@@ -0,0 +1,35 @@
+package pushover
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// minClockSkew is the minimal detected clock skew that is corrected.
+// Smaller values are ignored because Date header has one second precision.
+const minClockSkew = 2 * time.Second
+
+// updateClockSkew updates clock skew from Date response header value.
+func (c *Client) updateClockSkew(date string) {
+	if date == "" {
+		return
+	}
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	skew := t.Sub(time.Now())
+	if skew > -minClockSkew && skew < minClockSkew {
+		skew = 0
+	}
+	atomic.StoreInt64(&c.clockSkew, int64(skew))
+}
+
+// ClockSkew returns the difference between Pushover server time and local time
+// detected from the last API response, if clock skew correction is enabled; see WithClockSkewCorrection.
+// It returns 0 if correction is disabled, or skew is not detected yet, or it is too small.
+func (c *Client) ClockSkew() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.clockSkew))
+}
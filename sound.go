@@ -0,0 +1,72 @@
+package pushover
+
+// Sound represents message sound.
+type Sound string
+
+// Message sound.
+const (
+	PushoverSound     Sound = "pushover" // default
+	BikeSound         Sound = "bike"
+	BugleSound        Sound = "bugle"
+	CashregisterSound Sound = "cashregister"
+	ClassicalSound    Sound = "classical"
+	CosmicSound       Sound = "cosmic"
+	FallingSound      Sound = "falling"
+	GamelanSound      Sound = "gamelan"
+	IncomingSound     Sound = "incoming"
+	IntermissionSound Sound = "intermission"
+	MagicSound        Sound = "magic"
+	MechanicalSound   Sound = "mechanical"
+	PianobarSound     Sound = "pianobar"
+	SirenSound        Sound = "siren"
+	SpacealarmSound   Sound = "spacealarm"
+	TugboatSound      Sound = "tugboat"
+	AlienSound        Sound = "alien"
+	ClimbSound        Sound = "climb"
+	PersistentSound   Sound = "persistent"
+	EchoSound         Sound = "echo"
+	UpdownSound       Sound = "updown"
+	VibrateSound      Sound = "vibrate" // vibrate only
+	NoneSound         Sound = "none"    // silent
+)
+
+// Sounds returns all built-in sounds.
+// Custom sounds uploaded for the application are not included.
+func Sounds() []Sound {
+	return []Sound{
+		PushoverSound,
+		BikeSound,
+		BugleSound,
+		CashregisterSound,
+		ClassicalSound,
+		CosmicSound,
+		FallingSound,
+		GamelanSound,
+		IncomingSound,
+		IntermissionSound,
+		MagicSound,
+		MechanicalSound,
+		PianobarSound,
+		SirenSound,
+		SpacealarmSound,
+		TugboatSound,
+		AlienSound,
+		ClimbSound,
+		PersistentSound,
+		EchoSound,
+		UpdownSound,
+		VibrateSound,
+		NoneSound,
+	}
+}
+
+// IsValid returns true if s is one of the built-in sounds.
+// Note that custom sounds uploaded for the application are valid too, but not reported as such.
+func (s Sound) IsValid() bool {
+	for _, sound := range Sounds() {
+		if s == sound {
+			return true
+		}
+	}
+	return false
+}
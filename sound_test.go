@@ -0,0 +1,15 @@
+package pushover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSound(t *testing.T) {
+	assert.Len(t, Sounds(), 23)
+	assert.True(t, SirenSound.IsValid())
+	assert.True(t, Sound("none").IsValid())
+	assert.False(t, Sound("Siren").IsValid())
+	assert.False(t, Sound("").IsValid())
+}
@@ -0,0 +1,111 @@
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SendLongMessage sends given message, splitting text longer than Pushover limit
+// into several sequentially numbered messages ("[1/3] …").
+// Text is split on line boundaries where possible.
+// Messages are sent one by one; sending stops on the first error.
+// Room is left in each part for labels from ctx.
+// Emergency parameters, tags and attachment are sent only with the first part;
+// emergency messages are continued with high priority ones.
+func (c *Client) SendLongMessage(ctx context.Context, message *Message) error {
+	max := MaxMessageLength - utf8.RuneCountInString(labelsSuffix(ctx, message.HTML))
+	if max < MaxMessageLength/2 {
+		// very long labels are cut by the client instead
+		max = MaxMessageLength / 2
+	}
+	parts := splitMessage(message.Message, max)
+	if len(parts) == 1 {
+		return c.SendMessage(ctx, message)
+	}
+
+	for i, part := range parts {
+		m := *message
+		if i > 0 {
+			m = *continuationMessage(message)
+		}
+		m.Message = fmt.Sprintf("[%d/%d] %s", i+1, len(parts), part)
+		if err := c.SendMessage(ctx, &m); err != nil {
+			return fmt.Errorf("pushover: failed to send part %d/%d: %w", i+1, len(parts), err)
+		}
+	}
+	return nil
+}
+
+// continuationMessage returns a copy of message for the second and next parts of long message:
+// without emergency parameters, tags and attachment, so it does not page, is not suppressed
+// as a duplicate of the first part, and does not repeat the attachment.
+func continuationMessage(message *Message) *Message {
+	m := message
+	if m.Priority == EmergencyPriority {
+		m = downgradeMessage(m)
+	}
+
+	res := *m
+	res.Tags = nil
+	res.Attachment, res.AttachmentName, res.AttachmentType = nil, "", ""
+	return &res
+}
+
+// splitMessage splits text into parts that, with "[i/n] " prefix, fit into max characters.
+// It returns a single part if text fits into max characters as is.
+func splitMessage(text string, max int) []string {
+	if utf8.RuneCountInString(text) <= max {
+		return []string{text}
+	}
+
+	// increase prefix width until the number of parts fits into it
+	for digits := 1; ; digits++ {
+		prefix := len("[/] ") + 2*digits
+		parts := splitLines(text, max-prefix)
+		if len(strconv.Itoa(len(parts))) <= digits {
+			return parts
+		}
+	}
+}
+
+// splitLines splits text into parts of at most size characters, on line boundaries where possible.
+func splitLines(text string, size int) []string {
+	var parts []string
+	var cur strings.Builder
+	var curLen int
+
+	flush := func() {
+		if curLen > 0 {
+			parts = append(parts, strings.TrimSuffix(cur.String(), "\n"))
+			cur.Reset()
+			curLen = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		l := utf8.RuneCountInString(strings.TrimSuffix(line, "\n"))
+		if curLen+l > size {
+			flush()
+		}
+
+		// split too long line
+		for l > size {
+			r := []rune(line)
+			parts = append(parts, string(r[:size]))
+			line = string(r[size:])
+			l -= size
+		}
+
+		if line == "" {
+			continue
+		}
+		cur.WriteString(line)
+		curLen += utf8.RuneCountInString(line)
+	}
+	flush()
+
+	return parts
+}
@@ -0,0 +1,95 @@
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitMessage(t *testing.T) {
+	t.Run("Short", func(t *testing.T) {
+		assert.Equal(t, []string{"short\n"}, splitMessage("short\n", 10))
+	})
+
+	t.Run("Lines", func(t *testing.T) {
+		text := "line one\nline two\nline three"
+		expected := []string{"line one", "line two", "line three"}
+		assert.Equal(t, expected, splitMessage(text, 20))
+	})
+
+	t.Run("LongLine", func(t *testing.T) {
+		text := "ab\n" + strings.Repeat("ж", 25)
+		expected := []string{"ab", strings.Repeat("ж", 14), strings.Repeat("ж", 11)}
+		assert.Equal(t, expected, splitMessage(text, 20))
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		lines := make([]string, 500)
+		for i := range lines {
+			lines[i] = strings.Repeat("x", i%70)
+		}
+		parts := splitMessage(strings.Join(lines, "\n"), MaxMessageLength)
+		assert.Greater(t, len(parts), 9)
+		for _, p := range parts {
+			assert.LessOrEqual(t, utf8.RuneCountInString(p), MaxMessageLength-len("[10/10] "))
+		}
+	})
+}
+
+func TestSendLongMessage(t *testing.T) {
+	var m sync.Mutex
+	var requests []url.Values
+	var attachments int
+	var receipts int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == "GET" {
+			rw.Write([]byte(`{"status":1,"request":"r","acknowledged":0}`))
+			return
+		}
+
+		if err := req.ParseMultipartForm(1 << 20); err != http.ErrNotMultipart {
+			require.NoError(t, err)
+		}
+		m.Lock()
+		requests = append(requests, req.Form)
+		if _, _, err := req.FormFile("attachment"); err == nil {
+			attachments++
+		}
+		m.Unlock()
+		fmt.Fprintf(rw, `{"status":1,"request":"r","receipt":"r%d"}`, atomic.AddInt32(&receipts, 1))
+	}, WithEmergencyDeduplication())
+
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 50)
+	}
+	message := &Message{
+		User:       testUser,
+		Message:    strings.Join(lines, "\n"),
+		Priority:   EmergencyPriority,
+		Retry:      60,
+		Expire:     600,
+		Tags:       []string{"db"},
+		Attachment: strings.NewReader("image data"),
+	}
+	require.NoError(t, c.SendLongMessage(context.Background(), message))
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, 1, attachments)
+	assert.Equal(t, "2", requests[0].Get("priority"))
+	assert.Equal(t, "db", requests[0].Get("tags"))
+	assert.Equal(t, "60", requests[0].Get("retry"))
+	assert.True(t, strings.HasPrefix(requests[1].Get("message"), "[2/2] "))
+	assert.Equal(t, "1", requests[1].Get("priority"))
+	assert.Empty(t, requests[1].Get("tags"))
+	assert.Empty(t, requests[1].Get("retry"))
+}
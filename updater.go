@@ -0,0 +1,80 @@
+package pushover
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultGlanceUpdaterInterval = time.Minute
+	maxGlanceUpdaterBackoff      = 32
+)
+
+// GlanceUpdater periodically sends glance updates.
+//
+// Updates are skipped if glance data did not change since the last successful send.
+// If Pushover API reports that rate limit is exceeded, the interval is doubled until the next successful send.
+type GlanceUpdater struct {
+	Client   *Client
+	Interval time.Duration // defaults to 1 minute
+
+	// Glance returns current glance values; it is called every interval.
+	Glance func(ctx context.Context) (*Glance, error)
+
+	// OnError, if set, is called for every error returned by Glance or SendGlance.
+	OnError func(err error)
+}
+
+// Run sends glance updates until ctx is canceled.
+// The first update is sent immediately.
+func (u *GlanceUpdater) Run(ctx context.Context) error {
+	interval := u.Interval
+	if interval <= 0 {
+		interval = defaultGlanceUpdaterInterval
+	}
+
+	var last string
+	backoff := 1
+	for {
+		sent, err := u.update(ctx, last)
+		switch {
+		case err == nil:
+			last = sent
+			backoff = 1
+		case isRateLimited(err):
+			if backoff < maxGlanceUpdaterBackoff {
+				backoff *= 2
+			}
+		}
+		if err != nil && u.OnError != nil && ctx.Err() == nil {
+			u.OnError(err)
+		}
+
+		t := time.NewTimer(interval * time.Duration(backoff))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// update sends glance update if it differs from last sent data.
+// It returns sent (or skipped) data.
+func (u *GlanceUpdater) update(ctx context.Context, last string) (string, error) {
+	g, err := u.Glance(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	data := u.Client.makeGlanceData(g).Encode()
+	if data == last {
+		return data, nil
+	}
+
+	if err = u.Client.SendGlance(ctx, g); err != nil {
+		return "", err
+	}
+	return data, nil
+}
@@ -0,0 +1,41 @@
+package pushover
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlanceUpdater(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.Write([]byte(`{"status":1,"request":"r"}`))
+	})
+
+	var calls int32
+	u := &GlanceUpdater{
+		Client:   c,
+		Interval: 10 * time.Millisecond,
+		Glance: func(ctx context.Context) (*Glance, error) {
+			// change value only once, after the third call
+			count := 1
+			if atomic.AddInt32(&calls, 1) > 3 {
+				count = 2
+			}
+			return &Glance{User: "user", Count: &count}, nil
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := u.Run(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	assert.Greater(t, atomic.LoadInt32(&calls), int32(3))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}